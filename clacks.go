@@ -1,6 +1,31 @@
 package main
 
+import (
+	"fmt"
+	"os"
+)
+
 func main() {
-	cont := NewController()
+	options, command, err := parseOptions(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	cont := NewController(options)
+	if len(command) > 0 {
+		err = cont.runCommand(command, os.Stdout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	err = cont.offerStarterConfig(os.Stdin, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 	cont.setupAndLaunchUILoop()
 }
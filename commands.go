@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `usage: clacks [options] [command]
+
+Without a command the terminal reader is started.
+
+options:
+  --config <file>       config file, by default feeds.json in $XDG_CONFIG_HOME/clacks, ~/.config/clacks
+                        or the working directory
+  --data-dir <dir>      directory the entry store is kept in, by default $XDG_DATA_HOME/clacks
+  --user-agent <agent>  user agent sent when fetching feeds and articles
+  --timeout <duration>  how long fetching a feed or article can take, 30s by default
+
+commands:
+  import <file.opml>  add the feeds in an OPML file to the config
+  export              print the config as OPML
+  list-feeds          list the feeds in the config with their entry and unread counts
+  fetch               fetch every feed into the entry store
+  entries <feed>      list the stored entries of a feed by url or name, or of all feeds
+      --since <when>  only entries newer than a duration such as 24h or a date such as 2021-05-03
+      --unread        only unread entries
+  show <entry-id>     show an entry listed by the entries command
+  discover <url>      list the feeds a web site links to or has at common feed paths
+
+list-feeds, fetch, entries, show and discover take --format text, json or tsv.
+`
+
+// runCommand run a command line subcommand instead of the terminal ui, output is written to out
+func (controller *Controller) runCommand(args []string, out io.Writer) error {
+	switch args[0] {
+	case "import":
+		if len(args) != 2 {
+			return errors.New(usage)
+		}
+		return controller.importOPML(args[1], out)
+	case "export":
+		if len(args) != 1 {
+			return errors.New(usage)
+		}
+		return controller.exportOPML(out)
+	case "list-feeds":
+		return controller.listFeeds(args[1:], out)
+	case "fetch":
+		return controller.fetch(args[1:], out)
+	case "entries":
+		return controller.listEntries(args[1:], out)
+	case "show":
+		return controller.showEntry(args[1:], out)
+	case "discover":
+		return controller.discover(args[1:], out)
+	default:
+		return errors.New(usage)
+	}
+}
+
+// add feeds from an opml file to the config file, creating the config file if there isn't one
+func (controller *Controller) importOPML(fileName string, out io.Writer) error {
+	opmlFile, err := os.Open(fileName)
+	if err != nil {
+		return errors.New("error: could not open opml file " + fileName)
+	}
+	defer opmlFile.Close()
+
+	imported, err := parseOPML(opmlFile)
+	if err != nil {
+		return err
+	}
+
+	config := &ConfigData{}
+	if _, statErr := os.Stat(controller.configFileName); statErr == nil {
+		data := NewData(nil)
+		err = data.loadJSONConfig(controller.configFileName)
+		if err != nil {
+			return err
+		}
+		config = data.configData
+	}
+
+	added := config.addFeeds(imported.Feeds)
+	err = writeConfigFile(controller.configFileName, config)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(out, "Imported %d of %d feeds into %s\n", added, len(imported.Feeds), controller.configFileName)
+	return err
+}
+
+// write the feeds in the config file out as opml
+func (controller *Controller) exportOPML(out io.Writer) error {
+	data := NewData(nil)
+	err := data.loadJSONConfig(controller.configFileName)
+	if err != nil {
+		return err
+	}
+	return writeOPML(out, data.configData)
+}
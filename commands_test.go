@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunCommandWithUnknownCommand(t *testing.T) {
+	controller := Controller{configFileName: configFileName}
+
+	err := controller.runCommand([]string{"blah"}, &bytes.Buffer{})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, usage, err.Error())
+}
+
+func TestImportOPMLCreatesConfig(t *testing.T) {
+	dir := t.TempDir()
+	opmlFileName := filepath.Join(dir, "subscriptions.opml")
+	assert.Nil(t, ioutil.WriteFile(opmlFileName, []byte(testOPML), 0600))
+
+	controller := Controller{configFileName: filepath.Join(dir, configFileName)}
+
+	var out bytes.Buffer
+	err := controller.runCommand([]string{"import", opmlFileName}, &out)
+	assert.Nil(t, err)
+	assert.Equal(t, "Imported 3 of 3 feeds into "+controller.configFileName+"\n", out.String())
+
+	data := NewData(nil)
+	assert.Nil(t, data.loadJSONConfig(controller.configFileName))
+	assert.Equal(t, 3, len(data.configData.Feeds))
+	assert.Equal(t, "Tech/Blogs", data.configData.Feeds[2].Category)
+}
+
+func TestImportOPMLSkipsFeedsAlreadyInConfig(t *testing.T) {
+	dir := t.TempDir()
+	opmlFileName := filepath.Join(dir, "subscriptions.opml")
+	assert.Nil(t, ioutil.WriteFile(opmlFileName, []byte(testOPML), 0600))
+
+	existing, err := ioutil.ReadFile(configFileName)
+	assert.Nil(t, err)
+	controller := Controller{configFileName: filepath.Join(dir, configFileName)}
+	assert.Nil(t, ioutil.WriteFile(controller.configFileName, existing, 0600))
+
+	var out bytes.Buffer
+	err = controller.runCommand([]string{"import", opmlFileName}, &out)
+	assert.Nil(t, err)
+	assert.Equal(t, "Imported 0 of 3 feeds into "+controller.configFileName+"\n", out.String())
+
+	data := NewData(nil)
+	assert.Nil(t, data.loadJSONConfig(controller.configFileName))
+	assert.Equal(t, 4, len(data.configData.Feeds))
+}
+
+func TestImportOPMLWithMissingFile(t *testing.T) {
+	controller := Controller{configFileName: filepath.Join(t.TempDir(), configFileName)}
+
+	err := controller.runCommand([]string{"import", "missing.opml"}, &bytes.Buffer{})
+
+	assert.Equal(t, "error: could not open opml file missing.opml", err.Error())
+}
+
+func TestExportOPML(t *testing.T) {
+	controller := Controller{configFileName: configFileName}
+
+	var out bytes.Buffer
+	err := controller.runCommand([]string{"export"}, &out)
+	assert.Nil(t, err)
+
+	config, err := parseOPML(&out)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(config.Feeds))
+	assert.Equal(t, "https://lifehacker.com/rss", config.Feeds[3].URL)
+}
+
+func TestExportOPMLWithMissingConfig(t *testing.T) {
+	controller := Controller{configFileName: "badfilename.json"}
+
+	err := controller.runCommand([]string{"export"}, &bytes.Buffer{})
+
+	assert.Equal(t, "error: could not find feeds.json config file", err.Error())
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// configWatchTick how often the config file is checked for changes
+const configWatchTick = 2 * time.Second
+
+// ConfigWatcher polls the config file and calls changed when its modification time or size changes,
+// polling works the same on every platform and with editors and sync tools that replace the file
+type ConfigWatcher struct {
+	fileName string
+	changed  func()
+	modTime  time.Time
+	size     int64
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewConfigWatcher factory method for config watcher, changes are looked for from the file as it is now
+func NewConfigWatcher(fileName string, changed func()) *ConfigWatcher {
+	watcher := &ConfigWatcher{fileName: fileName, changed: changed, stop: make(chan struct{})}
+	if info, err := os.Stat(fileName); err == nil {
+		watcher.modTime, watcher.size = info.ModTime(), info.Size()
+	}
+	return watcher
+}
+
+// Start check the config file every tick until Stop is called
+func (watcher *ConfigWatcher) Start(tick time.Duration) {
+	go func() {
+		ticker := time.NewTicker(tick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				watcher.check()
+			case <-watcher.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stop checking the config file
+func (watcher *ConfigWatcher) Stop() {
+	watcher.stopOnce.Do(func() {
+		close(watcher.stop)
+	})
+}
+
+// call changed if the config file has changed since it was last checked, a missing file isn't a change
+// so a file being replaced isn't read half way through
+func (watcher *ConfigWatcher) check() {
+	info, err := os.Stat(watcher.fileName)
+	if err != nil || (info.ModTime().Equal(watcher.modTime) && info.Size() == watcher.size) {
+		return
+	}
+	watcher.modTime, watcher.size = info.ModTime(), info.Size()
+	watcher.changed()
+}
+
+// read the config file again after it changed on disk and start using it, a config that can't be read
+// is reported and the config in use kept, changes to settings the ui is built from are only applied on restart
+func (ui *UI) reloadConfig() {
+	reloaded := NewData(nil)
+	err := reloaded.loadJSONConfig(ui.configFileName)
+	ui.app.QueueUpdateDraw(func() {
+		if err != nil {
+			ui.createMessagePage("Keeping the config in use, " + err.Error())
+			return
+		}
+		changed := restartSettings(ui.data.configData, reloaded.configData)
+		added := ui.applyConfig(reloaded.configData)
+		if len(added) > 0 {
+			go ui.data.fetchFeeds(added, ui.updateFeedRow)
+		}
+		if len(changed) > 0 {
+			ui.createMessagePage("Restart clacks to apply the changes to " + strings.Join(changed, ", "))
+		}
+	})
+}
+
+// restartSettings names of the settings that differ between two configs and are only read when the ui is
+// created, the key bindings, vim mode and theme
+func restartSettings(inUse *ConfigData, reloaded *ConfigData) []string {
+	var changed []string
+	if (len(inUse.Keys) > 0 || len(reloaded.Keys) > 0) && !reflect.DeepEqual(inUse.Keys, reloaded.Keys) {
+		changed = append(changed, "keys")
+	}
+	if inUse.VimMode != reloaded.VimMode {
+		changed = append(changed, "vim_mode")
+	}
+	if inUse.VimLeader != reloaded.VimLeader {
+		changed = append(changed, "vim_leader")
+	}
+	if inUse.Theme != reloaded.Theme {
+		changed = append(changed, "theme")
+	}
+	if (len(inUse.Themes) > 0 || len(reloaded.Themes) > 0) && !reflect.DeepEqual(inUse.Themes, reloaded.Themes) {
+		changed = append(changed, "themes")
+	}
+	return changed
+}
+
+// replace the config in use with config, feeds no longer listed are dropped and the feed list updated,
+// returns the urls of the feeds that weren't listed before and need fetching
+func (ui *UI) applyConfig(config *ConfigData) []string {
+	listed := make(map[string]bool)
+	for _, feed := range ui.data.configData.enabledFeeds() {
+		listed[feed.URL] = true
+	}
+	var added []string
+	for _, feed := range config.enabledFeeds() {
+		if !listed[feed.URL] {
+			added = append(added, feed.URL)
+		}
+		delete(listed, feed.URL)
+	}
+	// the config is set first so fetches still running for the removed feeds drop what they load
+	ui.data.setConfig(config)
+	for url := range listed {
+		ui.data.safeFeedData.Remove(url)
+	}
+	listedURL := ui.entriesURL
+	ui.populateFeedList()
+	// populating the feed list moves on from a feed that was removed, the entries still listed show the new config
+	if ui.entriesURL == listedURL {
+		ui.reloadEntries(ui.entriesURL)
+	}
+	return added
+}
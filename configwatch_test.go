@@ -0,0 +1,125 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConfigWatcherCallsChangedWhenFileChanges(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), configFileName)
+	assert.Nil(t, ioutil.WriteFile(fileName, []byte(`{"feeds": []}`), 0600))
+
+	changes := 0
+	watcher := NewConfigWatcher(fileName, func() { changes++ })
+	watcher.check()
+	assert.Equal(t, 0, changes)
+
+	assert.Nil(t, ioutil.WriteFile(fileName, []byte(`{"feeds": [{"url": "a"}]}`), 0600))
+	watcher.check()
+	watcher.check()
+	assert.Equal(t, 1, changes)
+
+	// a file touched without changing size still counts as changed
+	later := time.Now().Add(time.Minute)
+	assert.Nil(t, os.Chtimes(fileName, later, later))
+	watcher.check()
+	assert.Equal(t, 2, changes)
+
+	assert.Nil(t, os.Remove(fileName))
+	watcher.check()
+	assert.Equal(t, 2, changes)
+}
+
+func TestReloadConfigFetchesAddedAndDropsRemovedFeeds(t *testing.T) {
+	ui, app, fileName := setupSubscriptions(t)
+	ui.feedList.SetCurrentItem(2)
+	assert.Equal(t, testURLTwo, ui.entriesURL)
+
+	assert.Nil(t, writeConfigFile(fileName, &ConfigData{Feeds: []Feed{{URL: testURLOne, Name: "Renamed"}, {URL: "example.com/feed"}}}))
+	queued := len(app.QueuedUpdateDraws())
+	ui.reloadConfig()
+	app.QueuedUpdateDraws()[queued]()
+
+	assert.Equal(t, []string{"All (2)", "Renamed (2)", "Fetching example.com/feed"}, feedRows(ui))
+	assert.Empty(t, ui.data.safeFeedData.GetEntries(testURLTwo).entries)
+	// the removed feed's entries are no longer listed
+	assert.Equal(t, ui.getSelectedFeedURL(), ui.entriesURL)
+
+	// only the added feed is fetched
+	assert.Eventually(t, func() bool { return len(ui.data.safeFeedData.GetEntries("example.com/feed").entries) == 2 },
+		time.Second, 10*time.Millisecond)
+	parser := ui.data.parser.(*StubbedParser)
+	assert.Equal(t, 1, parser.callCount("example.com/feed"))
+	assert.Equal(t, 0, parser.callCount(testURLOne))
+	assert.Equal(t, 0, parser.callCount(testURLTwo))
+	frontPage, _ := ui.pages.GetFrontPage()
+	assert.Equal(t, feedPage, frontPage)
+}
+
+func TestReloadConfigKeepsSelectedEntry(t *testing.T) {
+	ui, app, fileName := setupSubscriptions(t)
+	ui.feedList.SetCurrentItem(1)
+	ui.entriesList.SetCurrentItem(1)
+
+	assert.Nil(t, writeConfigFile(fileName, &ConfigData{Feeds: []Feed{{URL: testURLOne, Name: "Renamed"}, {URL: testURLTwo}}}))
+	queued := len(app.QueuedUpdateDraws())
+	ui.reloadConfig()
+	app.QueuedUpdateDraws()[queued]()
+
+	assert.Equal(t, "Renamed (2)", feedRows(ui)[1])
+	assert.Equal(t, testURLOne, ui.entriesURL)
+	assert.Equal(t, 1, ui.entriesList.GetCurrentItem())
+}
+
+func TestReloadConfigAsksForRestartToApplyUISettings(t *testing.T) {
+	ui, app, fileName := setupSubscriptions(t)
+
+	config := ui.data.configData.copy()
+	config.Keys = map[string][]string{actionQuit: {"x"}}
+	config.Theme = "light"
+	config.ShowEntryDates = true
+	assert.Nil(t, writeConfigFile(fileName, config))
+	queued := len(app.QueuedUpdateDraws())
+	ui.reloadConfig()
+	app.QueuedUpdateDraws()[queued]()
+
+	frontPage, message := ui.pages.GetFrontPage()
+	assert.Equal(t, messagePage, frontPage)
+	screen := tcell.NewSimulationScreen("UTF-8")
+	assert.Nil(t, screen.Init())
+	defer screen.Fini()
+	screen.SetSize(150, 150)
+	message.Draw(screen)
+	assert.Contains(t, getScreenContents(screen), "Restart clacks to apply the changes to keys, theme")
+
+	// the reloaded config is kept whole so saving from the ui doesn't undo the edits
+	assert.Equal(t, "light", ui.data.configData.Theme)
+	assert.Equal(t, []string{"q"}, ui.keymap.keysFor(actionQuit))
+}
+
+func TestRestartSettings(t *testing.T) {
+	inUse := &ConfigData{Keys: map[string][]string{}, VimLeader: ","}
+	assert.Empty(t, restartSettings(inUse, &ConfigData{VimLeader: ",", ShowEntryDates: true, Concurrency: 2}))
+	assert.Equal(t, []string{"keys", "vim_mode", "vim_leader", "themes"}, restartSettings(inUse,
+		&ConfigData{Keys: map[string][]string{actionQuit: {"x"}}, VimMode: true, Themes: map[string]Theme{"mine": {}}}))
+}
+
+func TestReloadingMalformedConfigKeepsConfig(t *testing.T) {
+	ui, app, fileName := setupSubscriptions(t)
+	config := ui.data.configData
+
+	assert.Nil(t, ioutil.WriteFile(fileName, []byte(`{"feeds": [`), 0600))
+	queued := len(app.QueuedUpdateDraws())
+	ui.reloadConfig()
+	app.QueuedUpdateDraws()[queued]()
+
+	assert.Equal(t, config, ui.data.configData)
+	assert.Equal(t, 3, ui.feedList.GetItemCount())
+	frontPage, _ := ui.pages.GetFrontPage()
+	assert.Equal(t, messagePage, frontPage)
+}
@@ -7,13 +7,17 @@ import (
 	"github.com/rivo/tview"
 )
 
-// Controller this struct holds interfaces to external libraries along with ui struct and config filename
+// Controller this struct holds interfaces to external libraries along with ui struct, config filename
+// and the directory the entry store is kept in
 type Controller struct {
 	app             TermApplication
 	feedParser      FeedParser
+	articleFetcher  ArticleFetcher
+	feedDiscoverer  FeedDiscoverer
 	browserLauncher BrowserLauncherInterface
 	ui              *UI
 	configFileName  string
+	dataDir         string
 }
 
 // TermApplication interface for the terminal UI app
@@ -27,6 +31,7 @@ type TermApplication interface {
 	QueueUpdateDraw(f func()) *tview.Application
 	GetFocus() tview.Primitive
 	GetInputCapture() func(event *tcell.EventKey) *tcell.EventKey
+	SetAfterDrawFunc(handler func(screen tcell.Screen)) *tview.Application
 }
 
 // BrowserLauncherInterface interface to library for launching browser
@@ -42,37 +47,82 @@ func (BrowserLauncher) OpenDefault(fileOrURL string) error {
 	return osx.OpenDefault(fileOrURL)
 }
 
-// FeedParser interface to gofeed library for parsing atom/rss feeds
+// FeedParser interface to gofeed library for parsing atom/rss feeds, requests follow the settings of the feed
 type FeedParser interface {
-	ParseURL(feedURL string) (feed *gofeed.Feed, err error)
+	ParseURL(feedURL string, settings RequestSettings) (feed *gofeed.Feed, err error)
 }
 
-// NewController factory method to set up controller
-func NewController() *Controller {
-	feedParser := gofeed.NewParser()
-	feedParser.UserAgent = "Clacks - Terminal Atom/RSS Reader"
-	return &Controller{
+// ArticleFetcher interface to downloading the web page of an entry and extracting its article
+type ArticleFetcher interface {
+	FetchArticle(pageURL string, settings RequestSettings) (string, error)
+}
+
+// FeedDiscoverer interface to looking for the feeds of a web site
+type FeedDiscoverer interface {
+	DiscoverFeeds(pageURL string) ([]DiscoveredFeed, error)
+}
+
+// NewController factory method to set up controller, the config file is looked for when options don't name one
+func NewController(options Options) *Controller {
+	feedParser := NewHTTPFeedParser(nil)
+	articleFetcher := NewHTTPArticleFetcher()
+	feedDiscoverer := NewHTTPFeedDiscoverer()
+	if options.UserAgent != "" {
+		feedParser.UserAgent = options.UserAgent
+		articleFetcher.UserAgent = options.UserAgent
+		feedDiscoverer.UserAgent = options.UserAgent
+	}
+	if options.Timeout > 0 {
+		feedParser.client.Timeout = options.Timeout
+		articleFetcher.client.Timeout = options.Timeout
+		feedDiscoverer.client.Timeout = options.Timeout
+	}
+
+	controller := &Controller{
 		app:             tview.NewApplication(),
 		feedParser:      feedParser,
+		articleFetcher:  articleFetcher,
+		feedDiscoverer:  feedDiscoverer,
 		browserLauncher: BrowserLauncher{},
-		configFileName:  configFileName}
+		configFileName:  options.ConfigFile,
+		dataDir:         options.DataDir}
+	if controller.configFileName == "" {
+		controller.configFileName = findConfigFile()
+	}
+	if controller.dataDir == "" {
+		controller.dataDir = defaultDataDir()
+	}
+	return controller
 }
 
 func (controller *Controller) setupAndLaunchUILoop() {
-	// init threadsafe feed data
-	data := NewData(controller.feedParser)
-	err := data.loadJSONConfig(controller.configFileName)
+	// load config and what was saved in the entry store last time before fetching anything
+	data, store, err := controller.loadStoredData()
 	if err != nil {
 		panic(err)
 	}
+	defer store.Close()
 
 	// init ui elements
 	controller.ui = CreateUI(controller.app, data)
 
 	// Set Browser launcher
 	controller.ui.browserLauncher = controller.browserLauncher
+	// feeds added, renamed and removed in the ui are saved to the config file
+	controller.ui.configFileName = controller.configFileName
 
 	controller.ui.setInputCaptureHandler()
+	controller.ui.updateInterface()
+
+	// refresh feeds in the background as their refresh intervals pass
+	scheduler := NewScheduler(data, controller.ui.updateFeedRow)
+	scheduler.Start(schedulerTick)
+	defer scheduler.Stop()
+
+	// pick up changes made to the config file while clacks is running
+	watcher := NewConfigWatcher(controller.configFileName, controller.ui.reloadConfig)
+	watcher.Start(configWatchTick)
+	defer watcher.Stop()
 
 	// async call to load feed data
 	go controller.ui.loadAllFeedDataAndUpdateInterface()
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"github.com/mmcdole/gofeed"
 	"github.com/rivo/tview"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -9,15 +8,33 @@ import (
 )
 
 func TestNewController(t *testing.T) {
-	controller := NewController()
+	controller := NewController(Options{})
 
 	assert.IsType(t, &tview.Application{}, controller.app)
-	assert.IsType(t, &gofeed.Parser{}, controller.feedParser)
+	assert.IsType(t, &HTTPFeedParser{}, controller.feedParser)
 	assert.IsType(t, BrowserLauncher{}, controller.browserLauncher)
 
-	castParser, ok := controller.feedParser.(*gofeed.Parser)
+	castParser, ok := controller.feedParser.(*HTTPFeedParser)
 	assert.True(t, ok)
 	assert.Equal(t, "Clacks - Terminal Atom/RSS Reader", castParser.UserAgent)
+	assert.Equal(t, findConfigFile(), controller.configFileName)
+	assert.Equal(t, defaultDataDir(), controller.dataDir)
+}
+
+func TestNewControllerWithOptions(t *testing.T) {
+	controller := NewController(Options{ConfigFile: "/etc/clacks.json", DataDir: "/var/lib/clacks",
+		UserAgent: "test agent", Timeout: 5 * time.Second})
+
+	assert.Equal(t, "/etc/clacks.json", controller.configFileName)
+	assert.Equal(t, "/var/lib/clacks", controller.dataDir)
+
+	castParser := controller.feedParser.(*HTTPFeedParser)
+	assert.Equal(t, "test agent", castParser.UserAgent)
+	assert.Equal(t, 5*time.Second, castParser.client.Timeout)
+
+	castFetcher := controller.articleFetcher.(*HTTPArticleFetcher)
+	assert.Equal(t, "test agent", castFetcher.UserAgent)
+	assert.Equal(t, 5*time.Second, castFetcher.client.Timeout)
 }
 
 func TestStartUPLoopWithStubbedInterfaces(t *testing.T) {
@@ -28,7 +45,8 @@ func TestStartUPLoopWithStubbedInterfaces(t *testing.T) {
 
 	browserLauncherStub := StubbedBrowserLauncher{withError: false}
 
-	controllerWithStubs := Controller{app, parser, browserLauncherStub, nil, configFileName}
+	controllerWithStubs := Controller{app: app, feedParser: parser, browserLauncher: browserLauncherStub,
+		configFileName: configFileName, dataDir: t.TempDir()}
 
 	controllerWithStubs.setupAndLaunchUILoop()
 
@@ -36,11 +54,14 @@ func TestStartUPLoopWithStubbedInterfaces(t *testing.T) {
 
 	assert.Eventually(t, func() bool { return len(controllerWithStubs.ui.data.configData.Feeds) == 4 }, time.Second, 10*time.Millisecond)
 
-	for _, f := range controllerWithStubs.ui.app.(*StubbedApp).UpdateDraws {
+	stubbedApp := controllerWithStubs.ui.app.(*StubbedApp)
+	assert.Eventually(t, func() bool { return len(stubbedApp.QueuedUpdateDraws()) == 6 }, time.Second, 10*time.Millisecond)
+
+	for _, f := range stubbedApp.QueuedUpdateDraws() {
 		f()
 	}
 
-	assert.Equal(t, 4, controllerWithStubs.ui.feedList.GetItemCount())
+	assert.Equal(t, 5, controllerWithStubs.ui.feedList.GetItemCount())
 }
 
 func TestStartUPLoopWithConfigError(t *testing.T) {
@@ -51,7 +72,8 @@ func TestStartUPLoopWithConfigError(t *testing.T) {
 
 	browserLauncherStub := StubbedBrowserLauncher{withError: false}
 
-	controllerWithStubs := Controller{app, parser, browserLauncherStub, nil, ""}
+	controllerWithStubs := Controller{app: app, feedParser: parser, browserLauncher: browserLauncherStub,
+		configFileName: "", dataDir: t.TempDir()}
 
 	assert.PanicsWithError(t, "error: could not find feeds.json config file", func() { controllerWithStubs.setupAndLaunchUILoop() })
 
@@ -65,7 +87,8 @@ func TestStartUPLoopWithUIError(t *testing.T) {
 
 	browserLauncherStub := StubbedBrowserLauncher{withError: false}
 
-	controllerWithStubs := Controller{app, parser, browserLauncherStub, nil, configFileName}
+	controllerWithStubs := Controller{app: app, feedParser: parser, browserLauncher: browserLauncherStub,
+		configFileName: configFileName, dataDir: t.TempDir()}
 
 	assert.PanicsWithError(t, "stubbed ui error", func() { controllerWithStubs.setupAndLaunchUILoop() })
 
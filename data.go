@@ -4,51 +4,120 @@ import (
 	"encoding/json"
 	"errors"
 	strip "github.com/grokify/html-strip-tags-go"
+	"github.com/mmcdole/gofeed"
 	"io"
 
 	"html"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 )
 
-// Data struct holding config and feed data
+// Data struct holding config and feed data, store is optional and persists entries between runs,
+// a config is never changed once it is in use, changes are made to a copy that replaces it
 type Data struct {
 	safeFeedData *SafeFeedData
 	configData   *ConfigData
+	configMutex  sync.RWMutex
 	parser       FeedParser
+	store        EntryStore
+	articles     ArticleFetcher
+	discoverer   FeedDiscoverer
 }
 
 const configFileName = "feeds.json"
 
+// defaultConcurrency number of feeds fetched at the same time when config doesn't set one
+const defaultConcurrency = 8
+
 // ConfigData struct to unmarshall collection of urls from JSON config
 type ConfigData struct {
-	Feeds []Feed `json:"feeds"`
+	Feeds           []Feed              `json:"feeds"`
+	Concurrency     int                 `json:"concurrency,omitempty"`
+	RefreshInterval Duration            `json:"refresh_interval,omitempty"`
+	ShowEntryDates  bool                `json:"show_entry_dates,omitempty"`
+	Keys            map[string][]string `json:"keys,omitempty"`
+	VimMode         bool                `json:"vim_mode,omitempty"`
+	VimLeader       string              `json:"vim_leader,omitempty"`
+	Theme           string              `json:"theme,omitempty"`
+	Themes          map[string]Theme    `json:"themes,omitempty"`
 }
 
-// Feed struct to unmarshall individual feed url from JSON config
+// Feed struct to unmarshall individual feed url from JSON config, the other settings are optional,
+// name is shown in place of the feed's own title, feeds in reader mode show the full article fetched from
+// the web page of each entry and content picks the field of each entry shown
 type Feed struct {
-	URL string `json:"url"`
+	URL             string            `json:"url"`
+	Name            string            `json:"name,omitempty"`
+	Category        string            `json:"category,omitempty"`
+	RefreshInterval Duration          `json:"refresh_interval,omitempty"`
+	ReaderMode      bool              `json:"reader_mode,omitempty"`
+	UserAgent       string            `json:"user_agent,omitempty"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	Timeout         Duration          `json:"timeout,omitempty"`
+	MaxEntries      int               `json:"max_entries,omitempty"`
+	Disabled        bool              `json:"disabled,omitempty"`
+	Content         string            `json:"content,omitempty"`
+}
+
+// Duration time.Duration written in the JSON config as a string such as "30m" or "1h30m"
+type Duration time.Duration
+
+// MarshalJSON write duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON parse duration from a string
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var value string
+	err := json.Unmarshal(b, &value)
+	if err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
 }
 
-// Entry struct describes a single item in an atom feed
+// Entry struct describes a single item in an atom feed, feedURL is set when entries of feeds are listed together
+// and fullContent is the article fetched from the entry's web page in reader mode
 type Entry struct {
-	title   string
-	content string
-	url     string
+	guid        string
+	title       string
+	content     string
+	fullContent string
+	url         string
+	author      string
+	published   time.Time
+	updated     time.Time
+	categories  []string
+	read        bool
+	isNew       bool
+	feedURL     string
 }
 
-// FeedDataModel struct of an feed title and slice of entries
+// FeedDataModel struct of an feed title and slice of entries, err is set when the feed failed to load
+// fetched is when the feed was last fetched and refreshHint how often the publisher asks to be polled
 type FeedDataModel struct {
-	name    string
-	entries []Entry
+	name        string
+	entries     []Entry
+	err         error
+	fetched     time.Time
+	refreshHint time.Duration
 }
 
-// SafeFeedData Map of Urls Strings to []Entry with mutex for thread safety
+// SafeFeedData Map of Urls Strings to []Entry with mutex for thread safety, entries are kept in a search index
 type SafeFeedData struct {
 	mu       sync.Mutex
 	feedData map[string]FeedDataModel
+	index    *SearchIndex
 }
 
 // SetSiteData Adding stat strings to the map
@@ -56,6 +125,7 @@ func (c *SafeFeedData) SetSiteData(url string, data FeedDataModel) {
 	c.mu.Lock()
 	// Lock so only one goroutine at a time can access the map
 	c.feedData[url] = data
+	c.index.IndexFeed(url, data.entries)
 	c.mu.Unlock()
 }
 
@@ -67,14 +137,103 @@ func (c *SafeFeedData) GetEntries(url string) FeedDataModel {
 	return c.feedData[url]
 }
 
+// SetEntriesRead set read state of the entries of a feed with matching guids
+func (c *SafeFeedData) SetEntriesRead(url string, guids []string, read bool) {
+	c.mu.Lock()
+	// Lock so only one goroutine at a time can access the map
+	defer c.mu.Unlock()
+	feedData, ok := c.feedData[url]
+	if !ok {
+		return
+	}
+	// copy entries so slices already handed out by GetEntries aren't changed underneath their readers
+	entries := make([]Entry, len(feedData.entries))
+	copy(entries, feedData.entries)
+	for i := range entries {
+		for _, guid := range guids {
+			if entries[i].guid == guid {
+				entries[i].read = read
+			}
+		}
+	}
+	feedData.entries = entries
+	c.feedData[url] = feedData
+}
+
+// SetFullContent set the article fetched for the entry of a feed with a matching guid
+func (c *SafeFeedData) SetFullContent(url string, guid string, content string) {
+	c.mu.Lock()
+	// Lock so only one goroutine at a time can access the map
+	defer c.mu.Unlock()
+	feedData, ok := c.feedData[url]
+	if !ok {
+		return
+	}
+	// copy entries so slices already handed out by GetEntries aren't changed underneath their readers
+	entries := make([]Entry, len(feedData.entries))
+	copy(entries, feedData.entries)
+	for i := range entries {
+		if entries[i].guid == guid {
+			entries[i].fullContent = content
+		}
+	}
+	feedData.entries = entries
+	c.feedData[url] = feedData
+	c.index.IndexFeed(url, entries)
+}
+
+// SetFetchResult record when a feed was fetched and the error it failed with, nil when it loaded,
+// the feed's entries are left as they are
+func (c *SafeFeedData) SetFetchResult(url string, err error, fetched time.Time) {
+	c.mu.Lock()
+	// Lock so only one goroutine at a time can access the map
+	defer c.mu.Unlock()
+	feedData := c.feedData[url]
+	feedData.err = err
+	feedData.fetched = fetched
+	c.feedData[url] = feedData
+}
+
+// Remove remove the data of a feed that is no longer in the config
+func (c *SafeFeedData) Remove(url string) {
+	c.mu.Lock()
+	// Lock so only one goroutine at a time can access the map
+	delete(c.feedData, url)
+	c.index.IndexFeed(url, nil)
+	c.mu.Unlock()
+}
+
 // Clear clear all feed data, use before a refresh
 func (c *SafeFeedData) Clear() {
 	c.mu.Lock()
 	// Lock so only one goroutine at a time can access the map
 	c.feedData = make(map[string]FeedDataModel)
+	c.index.Clear()
 	c.mu.Unlock()
 }
 
+// config in use, safe to call from goroutines fetching feeds while the ui changes the config
+func (data *Data) config() *ConfigData {
+	data.configMutex.RLock()
+	defer data.configMutex.RUnlock()
+	return data.configData
+}
+
+// replace the config in use
+func (data *Data) setConfig(config *ConfigData) {
+	data.configMutex.Lock()
+	data.configData = config
+	data.configMutex.Unlock()
+}
+
+// copy of config that can be changed without changing the config in use
+func (config *ConfigData) copy() *ConfigData {
+	changed := *config
+	changed.Feeds = make([]Feed, len(config.Feeds))
+	copy(changed.Feeds, config.Feeds)
+	return &changed
+}
+
 // LoadJsonConfig load feeds from json file
 func (data *Data) loadJSONConfig(fileName string) error {
 	//open config file
@@ -82,6 +241,7 @@ func (data *Data) loadJSONConfig(fileName string) error {
 	if fileError != nil {
 		return fileError
 	}
+	defer configFile.Close()
 
 	config, parseError := parseConfig(configFile)
 	if parseError != nil {
@@ -105,9 +265,86 @@ func parseConfig(r io.Reader) (*ConfigData, error) {
 		return nil, errors.New("error reading feeds.json, please check structure")
 	}
 
+	// check key bindings now rather than when the ui starts
+	_, err = newKeymap(loadedFeeds.Keys)
+	if err != nil {
+		return nil, err
+	}
+	if loadedFeeds.VimLeader != "" {
+		_, err = canonicalKeyName(loadedFeeds.VimLeader)
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err = loadedFeeds.theme()
+	if err != nil {
+		return nil, err
+	}
+	err = loadedFeeds.checkFeedSettings()
+	if err != nil {
+		return nil, err
+	}
+
 	return &loadedFeeds, nil
 }
 
+// write config to fileName, a temporary file is written first and renamed so the config is never left half written,
+// an existing config keeps its file mode
+func writeConfigFile(fileName string, config *ConfigData) error {
+	byteValue, err := json.MarshalIndent(config, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(fileName), 0700)
+	}
+	if err != nil {
+		return errors.New("error writing config " + err.Error())
+	}
+
+	tempFile, err := ioutil.TempFile(filepath.Dir(fileName), filepath.Base(fileName)+".*.tmp")
+	if err != nil {
+		return errors.New("error writing config " + err.Error())
+	}
+	defer os.Remove(tempFile.Name())
+
+	_, err = tempFile.Write(append(byteValue, '\n'))
+	// the temporary file is only readable by its owner, the file it replaces keeps its own permissions
+	if info, statErr := os.Stat(fileName); err == nil && statErr == nil {
+		err = tempFile.Chmod(info.Mode().Perm())
+	}
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tempFile.Name(), fileName)
+	}
+	if err != nil {
+		return errors.New("error writing config " + err.Error())
+	}
+	return nil
+}
+
+// add feeds to the config skipping any whose url is already there, returns how many were added
+func (config *ConfigData) addFeeds(feeds []Feed) int {
+	added := 0
+	for _, feed := range feeds {
+		if config.hasFeed(feed.URL) {
+			continue
+		}
+		config.Feeds = append(config.Feeds, feed)
+		added++
+	}
+	return added
+}
+
+// check if a feed with url is in the config
+func (config *ConfigData) hasFeed(url string) bool {
+	for _, feed := range config.Feeds {
+		if feed.URL == url {
+			return true
+		}
+	}
+	return false
+}
+
 func openConfigFile(fileName string) (*os.File, error) {
 	configFile, err := os.Open(fileName)
 	if err != nil {
@@ -116,50 +353,278 @@ func openConfigFile(fileName string) (*os.File, error) {
 	return configFile, err
 }
 
-// LoadFeedData use gofeed library to load data from atom feed
+// LoadFeedData use gofeed library to load data from atom feed, following the settings of the feed in the config
 func (data *Data) loadFeedData(url string) error {
-	feedData, err := data.parser.ParseURL(url)
+	feed, _ := data.config().feed(url)
+	feedData, err := data.parser.ParseURL(url, feed.requestSettings())
+	if err == errNotModified {
+		return data.loadUnmodifiedFeed(url, feed)
+	}
 	if err != nil {
 		return errors.New("error loading feed: " + err.Error())
 	}
 
 	if len(feedData.Items) > 0 {
 		feedName := feedData.Title
-		entrySlice := make([]Entry, len(feedData.Items))
-		for i, item := range feedData.Items {
+		items := feedData.Items
+		if feed.MaxEntries > 0 && len(items) > feed.MaxEntries {
+			items = items[:feed.MaxEntries]
+		}
+		entrySlice := make([]Entry, len(items))
+		for i, item := range items {
 			entrySlice[i] = Entry{
-				title:   html.UnescapeString(strip.StripTags(item.Title)),
-				content: strings.TrimSpace(html.UnescapeString(strip.StripTags(item.Description))),
-				url:     item.Link,
+				guid:       itemGUID(item),
+				title:      html.UnescapeString(strip.StripTags(item.Title)),
+				content:    itemContent(item, feed.Content),
+				url:        item.Link,
+				author:     itemAuthor(item),
+				published:  parsedTime(item.PublishedParsed),
+				updated:    parsedTime(item.UpdatedParsed),
+				categories: item.Categories,
 			}
 		}
 		feedDataModel := FeedDataModel{name: feedName, entries: entrySlice}
-		data.safeFeedData.SetSiteData(url, feedDataModel)
+		if data.store != nil {
+			// show every entry ever seen for the feed, not just the ones still published
+			feedDataModel, err = data.store.MergeFeed(url, feedDataModel)
+			if err != nil {
+				return err
+			}
+			feedDataModel.entries = capEntries(feedDataModel.entries, feed.MaxEntries)
+		}
+		feedDataModel.refreshHint = feedRefreshHint(feedData)
+		data.setLoadedFeed(url, feedDataModel)
 		return nil
 	}
 
 	return errors.New("error feed at url: " + url + " has no entries")
 }
 
-// asynchronously fetch atom feeds and load the data into the interface
-func (data *Data) loadDataFromFeeds() error {
-	if data.configData == nil || len(data.configData.Feeds) == 0 {
-		return errors.New("error attempted to load feed data with no config set")
+// load the entries of a feed that hasn't changed since it was last downloaded from the store,
+// the publisher's refresh hint is kept from when it was last downloaded
+func (data *Data) loadUnmodifiedFeed(url string, feed Feed) error {
+	if data.store == nil {
+		return errors.New("error loading feed: " + errNotModified.Error())
+	}
+	feedDataModel, err := data.store.LoadFeed(url)
+	if err != nil {
+		return err
+	}
+	if len(feedDataModel.entries) == 0 {
+		return errors.New("error feed at url: " + url + " has no entries")
+	}
+	feedDataModel.entries = capEntries(feedDataModel.entries, feed.MaxEntries)
+	feedDataModel.refreshHint = data.safeFeedData.GetEntries(url).refreshHint
+	data.setLoadedFeed(url, feedDataModel)
+	return nil
+}
+
+// show the entries loaded for a feed, flagging the ones that weren't there before
+func (data *Data) setLoadedFeed(url string, feedDataModel FeedDataModel) {
+	previous := data.safeFeedData.GetEntries(url).entries
+	markNewEntries(feedDataModel.entries, previous)
+	keepFullContent(feedDataModel.entries, previous)
+	data.safeFeedData.SetSiteData(url, feedDataModel)
+}
+
+// set read state of entries in memory and in the store if there is one
+func (data *Data) setEntriesRead(url string, guids []string, read bool) error {
+	data.safeFeedData.SetEntriesRead(url, guids, read)
+	if data.store != nil {
+		return data.store.SetRead(url, guids, read)
 	}
+	return nil
+}
 
-	for i := 0; i < len(data.configData.Feeds); i++ {
-		atomFeedError := data.loadFeedData(data.configData.Feeds[i].URL)
-		if atomFeedError != nil {
-			return atomFeedError
+// mark entries read, the entries can come from several feeds as long as their feedURL is set
+func (data *Data) markEntriesRead(entries []Entry) error {
+	var urls []string
+	guids := make(map[string][]string)
+	for _, entry := range entries {
+		if _, ok := guids[entry.feedURL]; !ok {
+			urls = append(urls, entry.feedURL)
 		}
+		guids[entry.feedURL] = append(guids[entry.feedURL], entry.guid)
 	}
 
+	for _, url := range urls {
+		err := data.setEntriesRead(url, guids[url], true)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// number of entries in a feed that haven't been read
+func (feedData FeedDataModel) unreadCount() int {
+	count := 0
+	for _, entry := range feedData.entries {
+		if !entry.read {
+			count++
+		}
+	}
+	return count
+}
+
+// flag entries that weren't in the previous entries of a feed as new, nothing is new the first time a feed loads
+func markNewEntries(entries []Entry, previous []Entry) {
+	if len(previous) == 0 {
+		return
+	}
+	seen := make(map[string]bool, len(previous))
+	for _, entry := range previous {
+		seen[entry.guid] = true
+	}
+	for i := range entries {
+		entries[i].isNew = !seen[entries[i].guid]
+	}
+}
+
+// name of the first author of an item
+func itemAuthor(item *gofeed.Item) string {
+	if len(item.Authors) > 0 && item.Authors[0] != nil {
+		return item.Authors[0].Name
+	}
+	if item.Author != nil {
+		return item.Author.Name
+	}
+	return ""
+}
+
+// time parsed by gofeed, zero if the feed didn't have one
+func parsedTime(parsed *time.Time) time.Time {
+	if parsed == nil {
+		return time.Time{}
+	}
+	return *parsed
+}
+
+// date of an entry, when it was published or if it doesn't say when it was last updated
+func (entry Entry) date() time.Time {
+	if !entry.published.IsZero() {
+		return entry.published
+	}
+	return entry.updated
+}
+
+// html content of an item, the full content is preferred over the description when the feed has both unless
+// the feed's content setting prefers the description
+func itemContent(item *gofeed.Item, preferred string) string {
+	fields := []string{item.Content, item.Description}
+	if preferred == descriptionContent {
+		fields = []string{item.Description, item.Content}
+	}
+	for _, field := range fields {
+		if strings.TrimSpace(field) != "" {
+			return strings.TrimSpace(field)
+		}
+	}
+	return ""
+}
+
+// item guid used to identify entries in the store, falls back to link and title for feeds without one
+func itemGUID(item *gofeed.Item) string {
+	if item.GUID != "" {
+		return item.GUID
+	}
+	if item.Link != "" {
+		return item.Link
+	}
+	return item.Title
+}
+
+// load entries saved by previous runs so they can be shown before feeds are fetched, feeds that have been
+// removed from the config or disabled are left in the store
+func (data *Data) loadFeedsFromStore() error {
+	if data.store == nil {
+		return nil
+	}
+
+	feeds, err := data.store.LoadFeeds()
+	if err != nil {
+		return err
+	}
+	for url, feedData := range feeds {
+		feed, ok := data.configData.feed(url)
+		if !ok || feed.Disabled {
+			continue
+		}
+		feedData.entries = capEntries(feedData.entries, feed.MaxEntries)
+		data.safeFeedData.SetSiteData(url, feedData)
+	}
+	return nil
+}
+
+// fetch every atom feed in the config that isn't disabled, feedLoaded is called with the url of each feed
+// as it finishes, a config without any feeds has nothing to fetch
+func (data *Data) loadDataFromFeeds(feedLoaded func(url string)) error {
+	config := data.config()
+	if config == nil {
+		return errors.New("error attempted to load feed data with no config set")
+	}
+
+	var urls []string
+	for _, feed := range config.enabledFeeds() {
+		urls = append(urls, feed.URL)
+	}
+	data.fetchFeeds(urls, feedLoaded)
+
+	return nil
+}
+
+// fetch feeds using a pool of workers, feedLoaded is called with the url of each feed as it finishes
+func (data *Data) fetchFeeds(urls []string, feedLoaded func(url string)) {
+	urlChannel := make(chan string)
+	var wg sync.WaitGroup
+
+	for i := 0; i < data.concurrency(len(urls)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for url := range urlChannel {
+				data.fetchFeed(url)
+				if feedLoaded != nil {
+					feedLoaded(url)
+				}
+			}
+		}()
+	}
+
+	for _, url := range urls {
+		urlChannel <- url
+	}
+	close(urlChannel)
+	wg.Wait()
+}
+
+// fetch a single feed, a feed that fails to load has its error stored with its data so the other feeds still load
+func (data *Data) fetchFeed(url string) {
+	atomFeedError := data.loadFeedData(url)
+	// a feed removed from the config while it was being fetched is dropped rather than listed again
+	if feed, ok := data.config().feed(url); !ok || feed.Disabled {
+		data.safeFeedData.Remove(url)
+		return
+	}
+	// keep showing any entries already loaded for the feed alongside the error
+	data.safeFeedData.SetFetchResult(url, atomFeedError, time.Now())
+}
+
+// number of workers used to fetch feeds, never more than there are feeds to fetch
+func (data *Data) concurrency(feedCount int) int {
+	workers := data.config().Concurrency
+	if workers <= 0 {
+		workers = defaultConcurrency
+	}
+	if workers > feedCount {
+		workers = feedCount
+	}
+	return workers
+}
+
 // NewData factory method for data objects
 func NewData(parser FeedParser) *Data {
-	safeFeedData := &SafeFeedData{feedData: make(map[string]FeedDataModel)}
+	safeFeedData := &SafeFeedData{feedData: make(map[string]FeedDataModel), index: NewSearchIndex()}
 	allFeeds := &ConfigData{}
 	data := &Data{safeFeedData: safeFeedData, configData: allFeeds, parser: parser}
 	return data
@@ -3,8 +3,13 @@ package main
 import (
 	"github.com/mmcdole/gofeed"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 const badConfigFile = "malformed_config.json"
@@ -14,11 +19,11 @@ func TestLoadFeedData(t *testing.T) {
 	parser := createStubbedParser(&fakeFeed, false)
 
 	testFeed := Feed{URL: testURLOne}
-	testConfig := ConfigData{[]Feed{testFeed}}
+	testConfig := ConfigData{Feeds: []Feed{testFeed}}
 
 	data := NewData(parser)
 	data.configData = &testConfig
-	err := data.loadDataFromFeeds()
+	err := data.loadDataFromFeeds(nil)
 
 	assert.Nil(t, err)
 	feedDataModel := data.safeFeedData.GetEntries(testURLOne)
@@ -34,6 +39,168 @@ func TestLoadFeedData(t *testing.T) {
 	assert.Equal(t, fakeFeed.Items[1].Link, feedDataModel.entries[1].url)
 }
 
+func TestLoadFeedDataConcurrentlyCallsFeedLoadedForEachFeed(t *testing.T) {
+	fakeFeed := CreateTestFeed()
+	parser := createStubbedParser(&fakeFeed, false)
+
+	testConfig := ConfigData{
+		Feeds:       []Feed{{URL: testURLOne}, {URL: testURLTwo}, {URL: testURLOne + "/three"}},
+		Concurrency: 2,
+	}
+
+	data := NewData(parser)
+	data.configData = &testConfig
+
+	var mutex sync.Mutex
+	loaded := make(map[string]bool)
+	err := data.loadDataFromFeeds(func(url string) {
+		mutex.Lock()
+		loaded[url] = true
+		mutex.Unlock()
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(loaded))
+	for _, feed := range testConfig.Feeds {
+		assert.True(t, loaded[feed.URL])
+		assert.Equal(t, fakeFeed.Title, data.safeFeedData.GetEntries(feed.URL).name)
+	}
+}
+
+func TestConcurrencyDefaultsAndLimits(t *testing.T) {
+	data := NewData(nil)
+	assert.Equal(t, defaultConcurrency, data.concurrency(20))
+
+	data.configData.Concurrency = 3
+	assert.Equal(t, 3, data.concurrency(20))
+
+	data.configData.Concurrency = 50
+	assert.Equal(t, 20, data.concurrency(20))
+}
+
+func TestLoadFeedDataMergesWithStore(t *testing.T) {
+	store, err := OpenBoltStore(filepath.Join(t.TempDir(), storeFileName))
+	assert.Nil(t, err)
+	defer store.Close()
+
+	_, err = store.MergeFeed(testURLOne, FeedDataModel{name: "old name", entries: []Entry{{guid: "old", title: "Old Entry"}}})
+	assert.Nil(t, err)
+
+	fakeFeed := CreateTestFeed()
+	data := NewData(createStubbedParser(&fakeFeed, false))
+	data.configData = &ConfigData{Feeds: []Feed{{URL: testURLOne}}}
+	data.store = store
+
+	err = data.loadFeedsFromStore()
+	assert.Nil(t, err)
+	assert.Equal(t, "old name", data.safeFeedData.GetEntries(testURLOne).name)
+
+	err = data.loadFeedData(testURLOne)
+	assert.Nil(t, err)
+
+	feedDataModel := data.safeFeedData.GetEntries(testURLOne)
+	assert.Equal(t, fakeFeed.Title, feedDataModel.name)
+	assert.Equal(t, 3, len(feedDataModel.entries))
+	assert.Equal(t, fakeFeed.Items[0].Link, feedDataModel.entries[0].guid)
+	assert.Equal(t, "Old Entry", feedDataModel.entries[2].title)
+}
+
+func TestLoadFeedsFromStoreSkipsFeedsNotInConfig(t *testing.T) {
+	store, err := OpenBoltStore(filepath.Join(t.TempDir(), storeFileName))
+	assert.Nil(t, err)
+	defer store.Close()
+
+	for _, url := range []string{testURLOne, testURLTwo, testURLOne + "/disabled"} {
+		_, err = store.MergeFeed(url, FeedDataModel{name: url, entries: []Entry{{guid: "one", title: "Entry"}}})
+		assert.Nil(t, err)
+	}
+
+	data := NewData(nil)
+	data.configData = &ConfigData{Feeds: []Feed{{URL: testURLOne}, {URL: testURLOne + "/disabled", Disabled: true}}}
+	data.store = store
+
+	err = data.loadFeedsFromStore()
+	assert.Nil(t, err)
+	assert.Equal(t, testURLOne, data.safeFeedData.GetEntries(testURLOne).name)
+	assert.Equal(t, "", data.safeFeedData.GetEntries(testURLTwo).name)
+	assert.Equal(t, "", data.safeFeedData.GetEntries(testURLOne+"/disabled").name)
+	assert.Equal(t, 1, len(data.searchEntries("entry")))
+}
+
+func TestSetEntriesReadAndMarkEntriesRead(t *testing.T) {
+	data := createTestData(false)
+	assert.Equal(t, 2, data.safeFeedData.GetEntries(testURLOne).unreadCount())
+
+	before := data.safeFeedData.GetEntries(testURLOne).entries
+	err := data.setEntriesRead(testURLOne, []string{testURLOne + "/two"}, true)
+	assert.Nil(t, err)
+
+	assert.False(t, before[1].read)
+	assert.True(t, data.safeFeedData.GetEntries(testURLOne).entries[1].read)
+	assert.Equal(t, 1, data.safeFeedData.GetEntries(testURLOne).unreadCount())
+
+	err = data.markEntriesRead(data.feedEntries(testURLOne))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, data.safeFeedData.GetEntries(testURLOne).unreadCount())
+	assert.Equal(t, 2, data.safeFeedData.GetEntries(testURLTwo).unreadCount())
+}
+
+func TestMarkNewEntries(t *testing.T) {
+	entries := []Entry{{guid: "3"}, {guid: "2"}, {guid: "1"}}
+
+	markNewEntries(entries, nil)
+	assert.False(t, entries[0].isNew)
+
+	markNewEntries(entries, []Entry{{guid: "2"}, {guid: "1"}})
+	assert.True(t, entries[0].isNew)
+	assert.False(t, entries[1].isNew)
+	assert.False(t, entries[2].isNew)
+}
+
+func TestLoadFeedDataKeepsMetadata(t *testing.T) {
+	published := time.Date(2021, time.May, 3, 9, 30, 0, 0, time.UTC)
+	updated := published.Add(time.Hour)
+	fakeFeed := CreateTestFeed()
+	fakeFeed.Items[0].GUID = "guid-one"
+	fakeFeed.Items[0].Authors = []*gofeed.Person{{Name: "Simon Travaglia"}}
+	fakeFeed.Items[0].PublishedParsed = &published
+	fakeFeed.Items[0].UpdatedParsed = &updated
+	fakeFeed.Items[0].Categories = []string{"bofh"}
+	fakeFeed.Items[1].Author = &gofeed.Person{Name: "Deprecated Author"}
+	fakeFeed.Items[1].UpdatedParsed = &updated
+
+	data := NewData(createStubbedParser(&fakeFeed, false))
+	err := data.loadFeedData(testURLOne)
+	assert.Nil(t, err)
+
+	entries := data.safeFeedData.GetEntries(testURLOne).entries
+	assert.Equal(t, "guid-one", entries[0].guid)
+	assert.Equal(t, "Simon Travaglia", entries[0].author)
+	assert.Equal(t, published, entries[0].published)
+	assert.Equal(t, published, entries[0].date())
+	assert.Equal(t, []string{"bofh"}, entries[0].categories)
+
+	assert.Equal(t, "Deprecated Author", entries[1].author)
+	assert.True(t, entries[1].published.IsZero())
+	assert.Equal(t, updated, entries[1].date())
+}
+
+func TestItemGUIDFallsBackToLinkAndTitle(t *testing.T) {
+	assert.Equal(t, "guid", itemGUID(&gofeed.Item{GUID: "guid", Link: "link", Title: "title"}))
+	assert.Equal(t, "link", itemGUID(&gofeed.Item{Link: "link", Title: "title"}))
+	assert.Equal(t, "title", itemGUID(&gofeed.Item{Title: "title"}))
+}
+
+func TestItemContentPrefersContentOverDescription(t *testing.T) {
+	assert.Equal(t, "<p>full</p>", itemContent(&gofeed.Item{Content: " <p>full</p> ", Description: "summary"}, ""))
+	assert.Equal(t, "summary", itemContent(&gofeed.Item{Content: "  ", Description: "summary"}, contentContent))
+}
+
+func TestItemContentPrefersDescriptionWhenFeedAsks(t *testing.T) {
+	assert.Equal(t, "summary", itemContent(&gofeed.Item{Content: "<p>full</p>", Description: " summary "}, descriptionContent))
+	assert.Equal(t, "<p>full</p>", itemContent(&gofeed.Item{Content: "<p>full</p>"}, descriptionContent))
+}
+
 func TestLoadFeedDataWithError(t *testing.T) {
 	parser := createStubbedParser(nil, true)
 
@@ -47,30 +214,63 @@ func TestLoadFeedDataWithError(t *testing.T) {
 func TestLoadFeedDataWithoutConfig(t *testing.T) {
 	parser := createStubbedParser(nil, false)
 	data := NewData(parser)
+	data.configData = nil
 
-	err := data.loadDataFromFeeds()
+	err := data.loadDataFromFeeds(nil)
 
 	assert.NotNil(t, err)
 	assert.Equal(t, "error attempted to load feed data with no config set", err.Error())
 }
 
+func TestLoadFeedDataWithoutFeeds(t *testing.T) {
+	data := NewData(createStubbedParser(nil, true))
+
+	loaded := 0
+	err := data.loadDataFromFeeds(func(url string) {
+		loaded++
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, loaded)
+}
+
 func TestLoadFeedDataWithFeedWithNoEntries(t *testing.T) {
 	fakeFeedWithNoEntries := gofeed.Feed{
 		Title: "Test Feed Title From Parser",
 		Items: []*gofeed.Item{},
 	}
 	testFeed := Feed{URL: testURLOne}
-	testConfig := ConfigData{[]Feed{testFeed}}
+	testConfig := ConfigData{Feeds: []Feed{testFeed}}
 
 	parser := createStubbedParser(&fakeFeedWithNoEntries, false)
 	data := NewData(parser)
 
 	data.configData = &testConfig
 
-	err := data.loadDataFromFeeds()
+	err := data.loadDataFromFeeds(nil)
 
-	assert.NotNil(t, err)
-	assert.Equal(t, "error feed at url: "+testURLOne+" has no entries", err.Error())
+	assert.Nil(t, err)
+	feedErr := data.safeFeedData.GetEntries(testURLOne).err
+	assert.NotNil(t, feedErr)
+	assert.Equal(t, "error feed at url: "+testURLOne+" has no entries", feedErr.Error())
+}
+
+func TestLoadFeedDataWithOneBrokenFeed(t *testing.T) {
+	fakeFeed := CreateTestFeed()
+	parser := &StubbedParser{fakeFeed: &fakeFeed, failingURLs: map[string]bool{testURLTwo: true}}
+
+	data := NewData(parser)
+	data.configData = &ConfigData{Feeds: []Feed{{URL: testURLOne}, {URL: testURLTwo}}}
+
+	err := data.loadDataFromFeeds(nil)
+
+	assert.Nil(t, err)
+	assert.Nil(t, data.safeFeedData.GetEntries(testURLOne).err)
+	assert.Equal(t, 2, len(data.safeFeedData.GetEntries(testURLOne).entries))
+
+	brokenFeed := data.safeFeedData.GetEntries(testURLTwo)
+	assert.Equal(t, "error loading feed: stubbed parser error", brokenFeed.err.Error())
+	assert.Equal(t, 0, len(brokenFeed.entries))
 }
 
 func TestLoadJsonConfig(t *testing.T) {
@@ -127,6 +327,80 @@ func TestParseConfigWithGoodJson(t *testing.T) {
 	assert.Equal(t, "https://example.com/rss", configData.Feeds[1].URL)
 }
 
+func TestParseConfigWithRefreshIntervals(t *testing.T) {
+	configJSON := `{
+				  "refresh_interval": "45m",
+				  "feeds": [
+					{
+					  "url": "https://blah.com/rss",
+					  "refresh_interval": "2h"
+					}
+				  ]
+				}`
+
+	configData, err := parseConfig(strings.NewReader(configJSON))
+
+	assert.Nil(t, err)
+	assert.Equal(t, Duration(45*time.Minute), configData.RefreshInterval)
+	assert.Equal(t, Duration(2*time.Hour), configData.Feeds[0].RefreshInterval)
+
+	_, err = parseConfig(strings.NewReader(`{"refresh_interval": "often", "feeds": []}`))
+	assert.Equal(t, "error reading feeds.json, please check structure", err.Error())
+}
+
+func TestParseConfigWithKeys(t *testing.T) {
+	configData, err := parseConfig(strings.NewReader(`{"keys": {"next-entry": ["j", "ctrl-n"]}, "feeds": []}`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"j", "ctrl-n"}, configData.Keys[actionNextEntry])
+
+	_, err = parseConfig(strings.NewReader(`{"keys": {"jump": ["j"]}, "feeds": []}`))
+	assert.Equal(t, "error unknown action jump in keys config", err.Error())
+}
+
+func TestFetchFeedRecordsWhenFetched(t *testing.T) {
+	data := NewData(createStubbedParser(nil, true))
+	data.setConfig(&ConfigData{Feeds: []Feed{{URL: testURLOne}}})
+
+	before := time.Now()
+	data.fetchFeed(testURLOne)
+
+	feedData := data.safeFeedData.GetEntries(testURLOne)
+	assert.NotNil(t, feedData.err)
+	assert.False(t, feedData.fetched.Before(before))
+}
+
+func TestFetchFeedDropsFeedRemovedFromConfig(t *testing.T) {
+	fakeFeed := CreateTestFeed()
+	data := NewData(createStubbedParser(&fakeFeed, false))
+	data.setConfig(&ConfigData{Feeds: []Feed{{URL: testURLOne}, {URL: testURLTwo, Disabled: true}}})
+
+	data.fetchFeed(testURLTwo)
+	data.fetchFeed("example.com/removed")
+
+	for _, url := range []string{testURLTwo, "example.com/removed"} {
+		assert.Empty(t, data.safeFeedData.GetEntries(url).entries)
+		assert.True(t, data.safeFeedData.GetEntries(url).fetched.IsZero())
+	}
+
+	data.fetchFeed(testURLOne)
+	assert.Len(t, data.safeFeedData.GetEntries(testURLOne).entries, 2)
+}
+
+func TestSetFetchResultKeepsEntries(t *testing.T) {
+	data := NewData(nil)
+	data.safeFeedData.SetSiteData(testURLOne, FeedDataModel{name: "feed", entries: []Entry{{guid: "one"}}})
+	data.safeFeedData.SetEntriesRead(testURLOne, []string{"one"}, true)
+
+	fetched := time.Now()
+	data.safeFeedData.SetFetchResult(testURLOne, nil, fetched)
+
+	feedData := data.safeFeedData.GetEntries(testURLOne)
+	assert.Nil(t, feedData.err)
+	assert.True(t, fetched.Equal(feedData.fetched))
+	assert.True(t, feedData.entries[0].read)
+}
+
 func TestParseConfigWithMalformedJson(t *testing.T) {
 	badJSON := `{
 				  "feasdfasdfds": [
@@ -174,3 +448,44 @@ func CreateTestFeed() gofeed.Feed {
 	}
 	return fakeFeed
 }
+
+func TestWriteConfigFileRoundTrip(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), configFileName)
+	config := &ConfigData{Feeds: []Feed{{URL: testURLOne, Name: "Registry", Category: "News"}, {URL: testURLTwo}}}
+
+	err := writeConfigFile(fileName, config)
+	assert.Nil(t, err)
+
+	data := NewData(nil)
+	err = data.loadJSONConfig(fileName)
+	assert.Nil(t, err)
+	assert.Equal(t, config, data.configData)
+
+	files, err := ioutil.ReadDir(filepath.Dir(fileName))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(files))
+}
+
+func TestWriteConfigFileKeepsFileMode(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), configFileName)
+	err := ioutil.WriteFile(fileName, []byte("{}"), 0644)
+	assert.Nil(t, err)
+	err = os.Chmod(fileName, 0644)
+	assert.Nil(t, err)
+
+	err = writeConfigFile(fileName, &ConfigData{Feeds: []Feed{{URL: testURLOne}}})
+	assert.Nil(t, err)
+
+	info, err := os.Stat(fileName)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+}
+
+func TestAddFeedsSkipsDuplicates(t *testing.T) {
+	config := &ConfigData{Feeds: []Feed{{URL: testURLOne}}}
+
+	added := config.addFeeds([]Feed{{URL: testURLOne, Name: "dupe"}, {URL: testURLTwo}})
+
+	assert.Equal(t, 1, added)
+	assert.Equal(t, []Feed{{URL: testURLOne}, {URL: testURLTwo}}, config.Feeds)
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"github.com/mmcdole/gofeed"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// feedLinkTypes types of the link tags web pages point to their feeds with
+var feedLinkTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/feed+json": true,
+}
+
+// commonFeedPaths paths of a site feeds are often found at, tried when a page doesn't link to its feeds
+var commonFeedPaths = []string{"/feed", "/rss", "/rss.xml", "/atom.xml", "/feed.xml", "/index.xml", "/feed.json"}
+
+// probeTimeout how long a request for a common feed path can take, a site that is slow to answer for a path
+// most likely has no feed there
+const probeTimeout = 5 * time.Second
+
+// DiscoveredFeed feed found on a web site, the title is empty when the page linking to it didn't give one
+type DiscoveredFeed struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
+// HTTPFeedDiscoverer FeedDiscoverer that looks for feeds over http
+type HTTPFeedDiscoverer struct {
+	client    *http.Client
+	UserAgent string
+}
+
+// NewHTTPFeedDiscoverer factory method for feed discoverer
+func NewHTTPFeedDiscoverer() *HTTPFeedDiscoverer {
+	return &HTTPFeedDiscoverer{client: &http.Client{Timeout: defaultTimeout}, UserAgent: userAgent}
+}
+
+// DiscoverFeeds feeds of the web site at pageURL, pageURL itself when it is a feed, otherwise the feeds the page
+// links to or failing that feeds found at common paths of the site
+func (discoverer *HTTPFeedDiscoverer) DiscoverFeeds(pageURL string) ([]DiscoveredFeed, error) {
+	if !strings.Contains(pageURL, "://") {
+		pageURL = "https://" + pageURL
+	}
+	body, finalURL, err := discoverer.get(discoverer.client, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	if feed, ok := parseFeed(body); ok {
+		return []DiscoveredFeed{{URL: pageURL, Title: feed.Title}}, nil
+	}
+
+	feeds, err := linkedFeeds(body, finalURL)
+	if err != nil {
+		return nil, err
+	}
+	if len(feeds) == 0 {
+		feeds = discoverer.probeCommonPaths(finalURL)
+	}
+	if len(feeds) == 0 {
+		return nil, errors.New("no feeds found at " + pageURL)
+	}
+	return feeds, nil
+}
+
+// download the page at pageURL with client, returns its body and its url after any redirects
+func (discoverer *HTTPFeedDiscoverer) get(client *http.Client, pageURL string) ([]byte, *url.URL, error) {
+	request, err := http.NewRequest(http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	request.Header.Set("User-Agent", discoverer.UserAgent)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, nil, errors.New(response.Status)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, maxArticleSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, response.Request.URL, nil
+}
+
+// feeds at the common feed paths of the site of pageURL, the paths are all requested at the same time
+// and the feeds found listed in the order of commonFeedPaths
+func (discoverer *HTTPFeedDiscoverer) probeCommonPaths(pageURL *url.URL) []DiscoveredFeed {
+	client := clientFor(discoverer.client, RequestSettings{Timeout: probeTimeout})
+	if discoverer.client.Timeout > 0 && discoverer.client.Timeout < probeTimeout {
+		client = discoverer.client
+	}
+
+	found := make([]*DiscoveredFeed, len(commonFeedPaths))
+	var wg sync.WaitGroup
+	for i, path := range commonFeedPaths {
+		wg.Add(1)
+		go func(i int, feedURL string) {
+			defer wg.Done()
+			body, _, err := discoverer.get(client, feedURL)
+			if err != nil {
+				return
+			}
+			if feed, ok := parseFeed(body); ok {
+				found[i] = &DiscoveredFeed{URL: feedURL, Title: feed.Title}
+			}
+		}(i, pageURL.ResolveReference(&url.URL{Path: path}).String())
+	}
+	wg.Wait()
+
+	var feeds []DiscoveredFeed
+	for _, feed := range found {
+		if feed != nil {
+			feeds = append(feeds, *feed)
+		}
+	}
+	return feeds
+}
+
+// parse body as a feed, false when it isn't one
+func parseFeed(body []byte) (*gofeed.Feed, bool) {
+	if gofeed.DetectFeedType(bytes.NewReader(body)) == gofeed.FeedTypeUnknown {
+		return nil, false
+	}
+	feed, err := gofeed.NewParser().Parse(bytes.NewReader(body))
+	return feed, err == nil
+}
+
+// linkedFeeds feeds an html page points to with alternate link tags, relative links are resolved against pageURL
+func linkedFeeds(body []byte, pageURL *url.URL) ([]DiscoveredFeed, error) {
+	document, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var feeds []DiscoveredFeed
+	seen := make(map[string]bool)
+	var find func(node *html.Node)
+	find = func(node *html.Node) {
+		if node.Type == html.ElementNode && node.DataAtom == atom.Link && isFeedLink(node) {
+			if resolved, resolveErr := pageURL.Parse(strings.TrimSpace(attribute(node, "href"))); resolveErr == nil &&
+				!seen[resolved.String()] {
+				seen[resolved.String()] = true
+				feeds = append(feeds, DiscoveredFeed{URL: resolved.String(), Title: strings.TrimSpace(attribute(node, "title"))})
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			find(child)
+		}
+	}
+	find(document)
+	return feeds, nil
+}
+
+// whether a link tag points to a feed, its rel includes alternate and its type is one of feedLinkTypes
+func isFeedLink(node *html.Node) bool {
+	if strings.TrimSpace(attribute(node, "href")) == "" {
+		return false
+	}
+	linkType := strings.ToLower(strings.TrimSpace(strings.Split(attribute(node, "type"), ";")[0]))
+	if !feedLinkTypes[linkType] {
+		return false
+	}
+	for _, rel := range strings.Fields(strings.ToLower(attribute(node, "rel"))) {
+		if rel == "alternate" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSitePage = `<html><head>
+<link rel="alternate" type="application/rss+xml" title="Posts" href="/posts.rss">
+<link rel="Alternate" type="application/atom+xml; charset=utf-8" href="https://other.org/atom">
+<link rel="alternate" type="application/feed+json" title="Posts again" href="/posts.rss">
+<link rel="stylesheet" type="text/css" href="/style.css">
+<link rel="alternate" type="text/html" hreflang="fr" href="/fr">
+</head><body><p>Welcome</p></body></html>`
+
+// serves a site whose home page links to its feeds, a blog without links but with a feed at /feed
+// and testRSS at /posts.rss
+func createTestSite() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(testSitePage))
+		case "/blog/":
+			_, _ = w.Write([]byte(`<html><head><title>Blog</title></head><body>No links here</body></html>`))
+		case "/posts.rss", "/feed":
+			_, _ = w.Write([]byte(testRSS))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestDiscoverFeedsLinkedFromPage(t *testing.T) {
+	server := createTestSite()
+	defer server.Close()
+
+	feeds, err := NewHTTPFeedDiscoverer().DiscoverFeeds(server.URL + "/")
+	assert.Nil(t, err)
+	assert.Equal(t, []DiscoveredFeed{{URL: server.URL + "/posts.rss", Title: "Posts"}, {URL: "https://other.org/atom"}}, feeds)
+}
+
+func TestDiscoverFeedsAtCommonPaths(t *testing.T) {
+	server := createTestSite()
+	defer server.Close()
+
+	feeds, err := NewHTTPFeedDiscoverer().DiscoverFeeds(server.URL + "/blog/")
+	assert.Nil(t, err)
+	assert.Equal(t, []DiscoveredFeed{{URL: server.URL + "/feed", Title: "Test RSS Feed"}}, feeds)
+
+	// a feed is found as itself
+	feeds, err = NewHTTPFeedDiscoverer().DiscoverFeeds(server.URL + "/posts.rss")
+	assert.Nil(t, err)
+	assert.Equal(t, []DiscoveredFeed{{URL: server.URL + "/posts.rss", Title: "Test RSS Feed"}}, feeds)
+}
+
+func TestDiscoverFeedsProbesCommonPathsAtOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`<html><body>No links here</body></html>`))
+		case "/rss", "/feed.json":
+			time.Sleep(200 * time.Millisecond)
+			_, _ = w.Write([]byte(testRSS))
+		default:
+			time.Sleep(200 * time.Millisecond)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	started := time.Now()
+	feeds, err := NewHTTPFeedDiscoverer().DiscoverFeeds(server.URL)
+	assert.Nil(t, err)
+	assert.Less(t, int64(time.Since(started)), int64(len(commonFeedPaths)*200*int(time.Millisecond)))
+	assert.Equal(t, []DiscoveredFeed{
+		{URL: server.URL + "/rss", Title: "Test RSS Feed"}, {URL: server.URL + "/feed.json", Title: "Test RSS Feed"}}, feeds)
+}
+
+func TestDiscoverFeedsFindingNothing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`<html><body>Nothing</body></html>`))
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPFeedDiscoverer().DiscoverFeeds(server.URL)
+	assert.Equal(t, "no feeds found at "+server.URL, err.Error())
+
+	_, err = NewHTTPFeedDiscoverer().DiscoverFeeds(server.URL + "/missing")
+	assert.Equal(t, "404 Not Found", err.Error())
+}
+
+func TestDiscoverCommand(t *testing.T) {
+	server := createTestSite()
+	defer server.Close()
+	controller := &Controller{feedDiscoverer: NewHTTPFeedDiscoverer()}
+
+	out, err := runHeadless(t, controller, "discover", "--format", "tsv", server.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, "url\ttitle\n"+server.URL+"/posts.rss\tPosts\nhttps://other.org/atom\t\n", out)
+
+	_, err = runHeadless(t, controller, "discover", server.URL+"/missing")
+	assert.Equal(t, "error discovering feeds: 404 Not Found", err.Error())
+
+	_, err = runHeadless(t, controller, "discover")
+	assert.Equal(t, usage, err.Error())
+}
+
+func TestAddFeedOffersDiscoveredFeeds(t *testing.T) {
+	ui, app, _ := setupSubscriptions(t)
+	ui.data.parser = createStubbedParser(nil, true)
+	ui.data.discoverer = StubbedFeedDiscoverer{feeds: []DiscoveredFeed{
+		{URL: "https://example.com/feed", Title: "Example [posts]"}, {URL: "https://example.com/comments"}}}
+
+	form := ui.createAddFeedPage()
+	ui.checkNewFeed(form, "example.com", func() { t.Fail() })
+	draws := app.QueuedUpdateDraws()
+	draws[len(draws)-1]()
+
+	assert.Equal(t, "Add Feed - found 2 feeds on the site, choose one and press Add", form.GetTitle())
+	urlField := form.GetFormItemByLabel("URL").(*tview.InputField)
+	assert.Equal(t, "https://example.com/feed", urlField.GetText())
+	found := form.GetFormItemByLabel("Found").(*tview.DropDown)
+	found.SetCurrentOption(1)
+	assert.Equal(t, "https://example.com/comments", urlField.GetText())
+	_, option := found.GetCurrentOption()
+	assert.True(t, strings.HasSuffix(option, "comments"))
+
+	// discovering again replaces the choices
+	ui.checkNewFeed(form, "example.com", func() { t.Fail() })
+	draws = app.QueuedUpdateDraws()
+	draws[len(draws)-1]()
+	assert.Equal(t, 4, form.GetFormItemCount())
+
+	// without any feeds found the parse error is shown
+	ui.data.discoverer = StubbedFeedDiscoverer{withError: true}
+	queued := len(app.QueuedUpdateDraws())
+	go ui.checkNewFeed(form, "example.com", func() { t.Fail() })
+	assert.Eventually(t, func() bool { return len(app.QueuedUpdateDraws()) > queued+1 }, time.Second, 10*time.Millisecond)
+	app.QueuedUpdateDraws()[queued]()
+	assert.Equal(t, "Add Feed - looking for feeds on the site example.com", form.GetTitle())
+	app.QueuedUpdateDraws()[queued+1]()
+	assert.Equal(t, "Add Feed - error loading feed: stubbed parser error", form.GetTitle())
+}
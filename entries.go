@@ -0,0 +1,91 @@
+package main
+
+import (
+	"sort"
+)
+
+// allFeedsURL stands in for a feed url on the feed list row that merges the entries of every feed
+const allFeedsURL = "clacks://all"
+
+// sortMode order entries are listed in
+type sortMode int
+
+const (
+	publisherOrder sortMode = iota
+	newestFirst
+	oldestFirst
+)
+
+// String description of sort mode shown in the title of the entries list
+func (mode sortMode) String() string {
+	switch mode {
+	case newestFirst:
+		return "newest first"
+	case oldestFirst:
+		return "oldest first"
+	default:
+		return "publisher order"
+	}
+}
+
+// next sort mode, used to cycle through them
+func (mode sortMode) next() sortMode {
+	return (mode + 1) % 3
+}
+
+// entries of a feed with the url of the feed set on each of them
+func (data *Data) feedEntries(url string) []Entry {
+	stored := data.safeFeedData.GetEntries(url).entries
+	entries := make([]Entry, len(stored))
+	for i, entry := range stored {
+		entry.feedURL = url
+		entries[i] = entry
+	}
+	return entries
+}
+
+// entries of every enabled feed in the config merged together
+func (data *Data) allEntries() []Entry {
+	var entries []Entry
+	for _, feed := range data.configData.enabledFeeds() {
+		entries = append(entries, data.feedEntries(feed.URL)...)
+	}
+	return entries
+}
+
+// refreshEntries update entries with the read state and article of the same entries of their feeds now,
+// entries that are no longer in their feed are left as they were
+func (data *Data) refreshEntries(entries []Entry) {
+	current := make(map[entryRef]Entry)
+	loaded := make(map[string]bool)
+	for i, entry := range entries {
+		if !loaded[entry.feedURL] {
+			loaded[entry.feedURL] = true
+			for _, feedEntry := range data.safeFeedData.GetEntries(entry.feedURL).entries {
+				current[entryRef{entry.feedURL, feedEntry.guid}] = feedEntry
+			}
+		}
+		if latest, ok := current[entryRef{entry.feedURL, entry.guid}]; ok {
+			entries[i].read = latest.read
+			entries[i].fullContent = latest.fullContent
+		}
+	}
+}
+
+// sort entries by date, entries without a date go last, publisher order leaves entries as they are
+func sortEntries(entries []Entry, mode sortMode) []Entry {
+	if mode == publisherOrder {
+		return entries
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		dateI, dateJ := entries[i].date(), entries[j].date()
+		if dateI.IsZero() || dateJ.IsZero() {
+			return !dateI.IsZero() && dateJ.IsZero()
+		}
+		if mode == oldestFirst {
+			return dateI.Before(dateJ)
+		}
+		return dateI.After(dateJ)
+	})
+	return entries
+}
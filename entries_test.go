@@ -0,0 +1,45 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSortModeCyclesThroughEveryMode(t *testing.T) {
+	assert.Equal(t, newestFirst, publisherOrder.next())
+	assert.Equal(t, oldestFirst, newestFirst.next())
+	assert.Equal(t, publisherOrder, oldestFirst.next())
+	assert.Equal(t, "publisher order", publisherOrder.String())
+}
+
+func TestSortEntriesPutsEntriesWithoutDateLast(t *testing.T) {
+	older := time.Date(2021, time.May, 3, 12, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	entries := func() []Entry {
+		return []Entry{{guid: "none"}, {guid: "older", published: older}, {guid: "newer", updated: newer}}
+	}
+
+	guids := func(entries []Entry) []string {
+		var result []string
+		for _, entry := range entries {
+			result = append(result, entry.guid)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"none", "older", "newer"}, guids(sortEntries(entries(), publisherOrder)))
+	assert.Equal(t, []string{"newer", "older", "none"}, guids(sortEntries(entries(), newestFirst)))
+	assert.Equal(t, []string{"older", "newer", "none"}, guids(sortEntries(entries(), oldestFirst)))
+}
+
+func TestAllEntriesSetsFeedURLOfEachEntry(t *testing.T) {
+	data := createTestData(false)
+
+	entries := data.allEntries()
+
+	assert.Len(t, entries, 4)
+	assert.Equal(t, testURLOne, entries[0].feedURL)
+	assert.Equal(t, testURLTwo, entries[3].feedURL)
+	assert.Equal(t, "", data.safeFeedData.GetEntries(testURLOne).entries[0].feedURL)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/rss"
+	"net/http"
+	"time"
+)
+
+const userAgent = "Clacks - Terminal Atom/RSS Reader"
+
+// defaultTimeout how long a feed request can take before it is abandoned
+const defaultTimeout = 30 * time.Second
+
+// errNotModified returned when a feed hasn't changed since it was last downloaded, its entries are in the store
+var errNotModified = errors.New("feed not modified")
+
+// HTTPCache interface to storage of feed responses used to make conditional requests
+type HTTPCache interface {
+	GetCachedResponse(url string) (CachedResponse, bool, error)
+	SetCachedResponse(url string, response CachedResponse) error
+}
+
+// CachedResponse validators from the last time a feed was downloaded, the feed's entries are kept in the store
+type CachedResponse struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// HTTPFeedParser FeedParser that sends ETag and Last-Modified validators, returns errNotModified on a 304
+type HTTPFeedParser struct {
+	client    *http.Client
+	parser    *gofeed.Parser
+	cache     HTTPCache
+	UserAgent string
+}
+
+// NewHTTPFeedParser factory method for feed parser, cache is optional and can be set later
+func NewHTTPFeedParser(cache HTTPCache) *HTTPFeedParser {
+	parser := gofeed.NewParser()
+	parser.RSSTranslator = &ttlRSSTranslator{}
+	return &HTTPFeedParser{
+		client:    &http.Client{Timeout: defaultTimeout},
+		parser:    parser,
+		cache:     cache,
+		UserAgent: userAgent,
+	}
+}
+
+// ParseURL download and parse the feed at feedURL, errNotModified when it hasn't changed since it was last downloaded
+func (feedParser *HTTPFeedParser) ParseURL(feedURL string, settings RequestSettings) (*gofeed.Feed, error) {
+	request, err := newRequest(feedURL, feedParser.UserAgent, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, found := feedParser.cachedResponse(feedURL)
+	if found {
+		if cached.ETag != "" {
+			request.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			request.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	response, err := clientFor(feedParser.client, settings).Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotModified && found {
+		return nil, errNotModified
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, gofeed.HTTPError{StatusCode: response.StatusCode, Status: response.Status}
+	}
+
+	feed, err := feedParser.parser.Parse(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	etag := response.Header.Get("ETag")
+	lastModified := response.Header.Get("Last-Modified")
+	if feedParser.cache != nil && (etag != "" || lastModified != "") {
+		// failing to cache only means the next request downloads the whole feed again
+		_ = feedParser.cache.SetCachedResponse(feedURL, CachedResponse{ETag: etag, LastModified: lastModified})
+	}
+
+	return feed, nil
+}
+
+// newRequest get request for url sent with userAgent, the user agent and headers of settings replace the defaults
+func newRequest(url string, userAgent string, settings RequestSettings) (*http.Request, error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("User-Agent", userAgent)
+	if settings.UserAgent != "" {
+		request.Header.Set("User-Agent", settings.UserAgent)
+	}
+	for name, value := range settings.Headers {
+		request.Header.Set(name, value)
+	}
+	return request, nil
+}
+
+// clientFor client used for a request with settings, a copy of client when settings have their own timeout
+func clientFor(client *http.Client, settings RequestSettings) *http.Client {
+	if settings.Timeout <= 0 {
+		return client
+	}
+	withTimeout := *client
+	withTimeout.Timeout = settings.Timeout
+	return &withTimeout
+}
+
+// look up the cached response for a feed, a cache that can't be read is treated as empty
+func (feedParser *HTTPFeedParser) cachedResponse(feedURL string) (CachedResponse, bool) {
+	if feedParser.cache == nil {
+		return CachedResponse{}, false
+	}
+	cached, found, err := feedParser.cache.GetCachedResponse(feedURL)
+	if err != nil || (cached.ETag == "" && cached.LastModified == "") {
+		return CachedResponse{}, false
+	}
+	return cached, found
+}
+
+// ttlRSSTranslator gofeed's rss translator keeping the rss ttl, which the universal feed drops, in Custom
+type ttlRSSTranslator struct {
+	gofeed.DefaultRSSTranslator
+}
+
+// Translate translate rss feed to the universal feed and copy over its ttl
+func (translator *ttlRSSTranslator) Translate(feed interface{}) (*gofeed.Feed, error) {
+	result, err := translator.DefaultRSSTranslator.Translate(feed)
+	if err != nil {
+		return nil, err
+	}
+	if rssFeed, ok := feed.(*rss.Feed); ok && rssFeed.TTL != "" {
+		if result.Custom == nil {
+			result.Custom = make(map[string]string)
+		}
+		result.Custom[ttlKey] = rssFeed.TTL
+	}
+	return result, nil
+}
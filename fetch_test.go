@@ -0,0 +1,165 @@
+package main
+
+import (
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testRSS = `<?xml version="1.0"?>
+<rss version="2.0">
+  <channel>
+    <title>Test RSS Feed</title>
+    <item><title>Item One</title><link>https://example.com/one</link><description>One</description></item>
+    <item><title>Item Two</title><link>https://example.com/two</link><description>Two</description></item>
+  </channel>
+</rss>`
+
+const testETag = `"v1"`
+const testLastModified = "Mon, 02 Jan 2006 15:04:05 GMT"
+
+// serves testRSS, answering with 304 when the request has the right validators and counting full downloads
+func createConditionalServer(downloads *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == testETag && r.Header.Get("If-Modified-Since") == testLastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		*downloads++
+		w.Header().Set("ETag", testETag)
+		w.Header().Set("Last-Modified", testLastModified)
+		_, _ = w.Write([]byte(testRSS))
+	}))
+}
+
+func TestHTTPFeedParserUsesCacheWhenNotModified(t *testing.T) {
+	downloads := 0
+	server := createConditionalServer(&downloads)
+	defer server.Close()
+
+	store, err := OpenBoltStore(filepath.Join(t.TempDir(), storeFileName))
+	assert.Nil(t, err)
+	defer store.Close()
+
+	feedParser := NewHTTPFeedParser(store)
+
+	feed, err := feedParser.ParseURL(server.URL, RequestSettings{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Test RSS Feed", feed.Title)
+	assert.Equal(t, 1, downloads)
+
+	cached, found, err := store.GetCachedResponse(server.URL)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, testETag, cached.ETag)
+	assert.Equal(t, testLastModified, cached.LastModified)
+
+	feed, err = feedParser.ParseURL(server.URL, RequestSettings{})
+	assert.Nil(t, feed)
+	assert.Equal(t, errNotModified, err)
+	assert.Equal(t, 1, downloads)
+}
+
+func TestLoadFeedDataUsesStoredEntriesWhenNotModified(t *testing.T) {
+	downloads := 0
+	server := createConditionalServer(&downloads)
+	defer server.Close()
+
+	store, err := OpenBoltStore(filepath.Join(t.TempDir(), storeFileName))
+	assert.Nil(t, err)
+	defer store.Close()
+
+	data := NewData(NewHTTPFeedParser(store))
+	data.configData = &ConfigData{Feeds: []Feed{{URL: server.URL}}}
+	data.store = store
+
+	assert.Nil(t, data.loadFeedData(server.URL))
+	data.safeFeedData.Clear()
+	assert.Nil(t, data.loadFeedData(server.URL))
+
+	assert.Equal(t, 1, downloads)
+	feedData := data.safeFeedData.GetEntries(server.URL)
+	assert.Equal(t, "Test RSS Feed", feedData.name)
+	assert.Equal(t, 2, len(feedData.entries))
+	assert.Equal(t, "https://example.com/two", feedData.entries[1].url)
+}
+
+func TestHTTPFeedParserWithoutCacheAlwaysDownloads(t *testing.T) {
+	downloads := 0
+	server := createConditionalServer(&downloads)
+	defer server.Close()
+
+	feedParser := NewHTTPFeedParser(nil)
+
+	_, err := feedParser.ParseURL(server.URL, RequestSettings{})
+	assert.Nil(t, err)
+	_, err = feedParser.ParseURL(server.URL, RequestSettings{})
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, downloads)
+}
+
+func TestHTTPFeedParserSendsUserAgent(t *testing.T) {
+	userAgentSent := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgentSent = r.UserAgent()
+		_, _ = w.Write([]byte(testRSS))
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPFeedParser(nil).ParseURL(server.URL, RequestSettings{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, userAgent, userAgentSent)
+}
+
+func TestHTTPFeedParserWithErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	feed, err := NewHTTPFeedParser(nil).ParseURL(server.URL, RequestSettings{})
+
+	assert.Nil(t, feed)
+	assert.Equal(t, gofeed.HTTPError{StatusCode: http.StatusNotFound, Status: "404 Not Found"}, err)
+}
+
+func TestHTTPFeedParserWithBadURL(t *testing.T) {
+	feed, err := NewHTTPFeedParser(nil).ParseURL("://not a url", RequestSettings{})
+
+	assert.Nil(t, feed)
+	assert.NotNil(t, err)
+}
+
+func TestHTTPFeedParserSendsFeedSettings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		if r.Header.Get("User-Agent") != "custom agent" || r.Header.Get("X-Token") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(testRSS))
+	}))
+	defer server.Close()
+
+	feedParser := NewHTTPFeedParser(nil)
+	_, err := feedParser.ParseURL(server.URL, RequestSettings{})
+	assert.NotNil(t, err)
+
+	settings := RequestSettings{UserAgent: "custom agent", Headers: map[string]string{"X-Token": "secret"}}
+	feed, err := feedParser.ParseURL(server.URL, settings)
+	assert.Nil(t, err)
+	assert.Equal(t, "Test RSS Feed", feed.Title)
+
+	settings.Timeout = 20 * time.Millisecond
+	_, err = feedParser.ParseURL(server.URL+"/slow", settings)
+	assert.NotNil(t, err)
+	assert.Equal(t, defaultTimeout, feedParser.client.Timeout)
+}
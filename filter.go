@@ -0,0 +1,203 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// patterns of tview's color tags and escaped square brackets
+var colorTagPattern = regexp.MustCompile(`^\[([a-zA-Z]*|#[0-9a-zA-Z]*|-)?(:([a-zA-Z]*|#[0-9a-zA-Z]*|-)?(:([lbdruis]+|-)?)?)?\]`)
+var escapedTagPattern = regexp.MustCompile(`^\[([a-zA-Z0-9_,;: \-\."#]+)\[(\[*)\]`)
+
+// taggedPart piece of a string with tview tags, either a color tag or text as it is displayed
+type taggedPart struct {
+	raw     string
+	visible string
+	tag     bool
+}
+
+// split text into color tags and the text displayed between them
+func splitTagged(text string) []taggedPart {
+	var parts []taggedPart
+	var plain strings.Builder
+	flushPlain := func() {
+		if plain.Len() > 0 {
+			parts = append(parts, taggedPart{raw: plain.String(), visible: plain.String()})
+			plain.Reset()
+		}
+	}
+
+	for len(text) > 0 {
+		if text[0] == '[' {
+			if tag := colorTagPattern.FindString(text); tag != "" {
+				flushPlain()
+				parts = append(parts, taggedPart{raw: tag, tag: true})
+				text = text[len(tag):]
+				continue
+			}
+			if match := escapedTagPattern.FindStringSubmatch(text); match != nil {
+				flushPlain()
+				parts = append(parts, taggedPart{raw: match[0], visible: "[" + match[1] + match[2] + "]"})
+				text = text[len(match[0]):]
+				continue
+			}
+		}
+		plain.WriteByte(text[0])
+		text = text[1:]
+	}
+	flushPlain()
+	return parts
+}
+
+// visibleText text as it is displayed without its color tags
+func visibleText(text string) string {
+	var visible strings.Builder
+	for _, part := range splitTagged(text) {
+		visible.WriteString(part.visible)
+	}
+	return visible.String()
+}
+
+// fuzzyMatch whether the characters of query appear in text in order ignoring case,
+// returns the positions of the matched runes of text
+func fuzzyMatch(text, query string) ([]int, bool) {
+	queryRunes := []rune(strings.ToLower(query))
+	var positions []int
+	i := 0
+	for position, r := range []rune(text) {
+		if i == len(queryRunes) {
+			break
+		}
+		if unicode.ToLower(r) == queryRunes[i] {
+			positions = append(positions, position)
+			i++
+		}
+	}
+	return positions, i == len(queryRunes)
+}
+
+// highlightMatches color the characters of text matched by query with highlight keeping text's own color tags
+func highlightMatches(text, query, highlight string) string {
+	positions, ok := fuzzyMatch(visibleText(text), query)
+	if !ok || len(positions) == 0 {
+		return text
+	}
+	matched := make(map[int]bool, len(positions))
+	for _, position := range positions {
+		matched[position] = true
+	}
+
+	var highlighted strings.Builder
+	foreground := "-"
+	position := 0
+	for _, part := range splitTagged(text) {
+		if part.tag {
+			// remember the foreground color so it can be restored after a highlighted character
+			if tagColor := strings.SplitN(strings.Trim(part.raw, "[]"), ":", 2)[0]; tagColor != "" {
+				foreground = tagColor
+			}
+			highlighted.WriteString(part.raw)
+			continue
+		}
+		if part.raw != part.visible {
+			// escaped brackets are left as they are
+			highlighted.WriteString(part.raw)
+			position += len([]rune(part.visible))
+			continue
+		}
+		for _, r := range part.raw {
+			if matched[position] && r != '[' && r != ']' {
+				highlighted.WriteString(tag(highlight) + string(r) + tag(foreground))
+			} else {
+				highlighted.WriteRune(r)
+			}
+			position++
+		}
+	}
+	return highlighted.String()
+}
+
+// matchesFilter whether a row of list is shown by the current filter, every row is shown when list isn't filtered
+func (ui *UI) matchesFilter(list *tview.List, text string) bool {
+	if ui.filterList != list || ui.filterQuery == "" {
+		return true
+	}
+	_, ok := fuzzyMatch(visibleText(text), ui.filterQuery)
+	return ok
+}
+
+// highlightFilter highlight the characters of a row of list matched by the current filter
+func (ui *UI) highlightFilter(list *tview.List, text string) string {
+	if ui.filterList != list || ui.filterQuery == "" {
+		return text
+	}
+	return highlightMatches(text, ui.filterQuery, ui.theme.Highlight)
+}
+
+// start filtering the focused list
+func (ui *UI) startFilter() {
+	switch ui.app.GetFocus() {
+	case ui.feedList:
+		ui.filterList = ui.feedList
+	case ui.entriesList:
+		ui.filterList = ui.entriesList
+	default:
+		return
+	}
+	ui.filterQuery = ""
+	ui.applyFilter(ui.filterList)
+}
+
+// stop filtering and show every row of the filtered list again
+func (ui *UI) stopFilter() {
+	list := ui.filterList
+	if list == nil {
+		return
+	}
+	ui.filterList = nil
+	ui.filterQuery = ""
+	ui.applyFilter(list)
+}
+
+// reload the rows of a list after its filter changed, the entries list is filtered from the entries already
+// looked up for it
+func (ui *UI) applyFilter(list *tview.List) {
+	switch {
+	case list == ui.feedList:
+		ui.populateFeedList()
+	case len(ui.unfiltered) == 0:
+		ui.reloadEntries(ui.entriesURL)
+	default:
+		ui.keepSelectedEntry(ui.listFilteredEntries)
+	}
+}
+
+// handle keys typed while filtering, returns false for keys that aren't part of the filter
+func (ui *UI) handleFilterKey(event *tcell.EventKey) bool {
+	switch event.Key() {
+	case tcell.KeyRune:
+		ui.filterQuery += string(event.Rune())
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if query := []rune(ui.filterQuery); len(query) > 0 {
+			ui.filterQuery = string(query[:len(query)-1])
+		}
+	case tcell.KeyEscape:
+		ui.stopFilter()
+		return true
+	default:
+		return false
+	}
+	ui.applyFilter(ui.filterList)
+	return true
+}
+
+// filter typed into a list shown in its title
+func (ui *UI) filterTitle(list *tview.List) string {
+	if ui.filterList != list {
+		return ""
+	}
+	return " filter: " + tview.Escape(ui.filterQuery)
+}
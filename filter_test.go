@@ -0,0 +1,113 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestVisibleTextRemovesColorTags(t *testing.T) {
+	assert.Equal(t, "new registry [title]", visibleText("[green]new[-] [::b]registry "+tview.Escape("[title]")))
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	positions, ok := fuzzyMatch("The Register", "trg")
+	assert.True(t, ok)
+	assert.Equal(t, []int{0, 4, 6}, positions)
+
+	_, ok = fuzzyMatch("The Register", "gt")
+	assert.True(t, ok)
+
+	_, ok = fuzzyMatch("The Register", "xyz")
+	assert.False(t, ok)
+}
+
+func TestHighlightMatchesKeepsColorTags(t *testing.T) {
+	assert.Equal(t, "[darkcyan][yellow]r[darkcyan]eg[-:-:-] [::b]t[yellow]i[-]tle",
+		highlightMatches("[darkcyan]reg[-:-:-] [::b]title", "ri", "yellow"))
+	assert.Equal(t, "no match", highlightMatches("no match", "xyz", "yellow"))
+}
+
+func TestFilterNarrowsFeedList(t *testing.T) {
+	data := createTestData(false)
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+	ui.setInputCaptureHandler()
+
+	capture := ui.app.GetInputCapture()
+	assert.Nil(t, capture(tcell.NewEventKey(tcell.KeyRune, 'f', 0)))
+	for _, r := range "gle" {
+		assert.Nil(t, capture(tcell.NewEventKey(tcell.KeyRune, r, 0)))
+	}
+
+	assert.Equal(t, 1, ui.feedList.GetItemCount())
+	feedName, url := ui.feedList.GetItemText(0)
+	assert.Equal(t, testURLTwo, url)
+	assert.Equal(t, "[yellow]g[-]oog[yellow]l[-][yellow]e[-] (2)", feedName)
+	assert.Equal(t, "Feeds filter: gle", ui.feedList.GetTitle())
+	assert.Equal(t, testURLTwo, ui.entriesURL)
+
+	capture(tcell.NewEventKey(tcell.KeyBackspace2, rune(0), 0))
+	assert.Equal(t, "gl", ui.filterQuery)
+
+	capture(tcell.NewEventKey(tcell.KeyEscape, rune(0), 0))
+	assert.Equal(t, 3, ui.feedList.GetItemCount())
+	assert.Equal(t, 2, ui.feedList.GetCurrentItem())
+	assert.Equal(t, "Feeds", ui.feedList.GetTitle())
+	assert.Equal(t, ui.feedList, ui.app.GetFocus())
+}
+
+func TestFilterNarrowsEntriesListUntilFocusMoves(t *testing.T) {
+	data := createTestData(false)
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+	ui.setInputCaptureHandler()
+	ui.switchAppFocus(ui.entriesList.Box, ui.feedList.Box, ui.entriesList)
+
+	capture := ui.app.GetInputCapture()
+	capture(tcell.NewEventKey(tcell.KeyRune, 'f', 0))
+	for _, r := range "regtwo" {
+		capture(tcell.NewEventKey(tcell.KeyRune, r, 0))
+	}
+
+	assert.Equal(t, 1, ui.entriesList.GetItemCount())
+	assert.Contains(t, ui.entryTextView.GetText(true), "registry fake content two")
+
+	// shortcuts are typed into the filter
+	capture(tcell.NewEventKey(tcell.KeyRune, 'u', 0))
+	assert.False(t, data.safeFeedData.GetEntries(testURLOne).entries[1].read)
+	assert.Equal(t, 0, ui.entriesList.GetItemCount())
+	capture(tcell.NewEventKey(tcell.KeyBackspace2, rune(0), 0))
+
+	ui.switchAppFocus(ui.feedList.Box, ui.entriesList.Box, ui.feedList)
+
+	assert.Nil(t, ui.filterList)
+	assert.Equal(t, 4, ui.entriesList.GetItemCount())
+	_, entryURL := ui.entriesList.GetItemText(ui.entriesList.GetCurrentItem())
+	assert.Equal(t, testURLOne+"/two", entryURL)
+}
+
+func TestFilterMatchesEntriesListedBeforeTyping(t *testing.T) {
+	data := createTestData(false)
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+	ui.setInputCaptureHandler()
+	ui.switchAppFocus(ui.entriesList.Box, ui.feedList.Box, ui.entriesList)
+
+	capture := ui.app.GetInputCapture()
+	capture(tcell.NewEventKey(tcell.KeyRune, 'f', 0))
+
+	// entries arriving while typing are listed once the feed is loaded again, not by the filter
+	feedData := data.safeFeedData.GetEntries(testURLOne)
+	feedData.entries = append(feedData.entries, Entry{guid: testURLOne + "/three", title: "registry three"})
+	data.safeFeedData.SetSiteData(testURLOne, feedData)
+	for _, r := range "three" {
+		capture(tcell.NewEventKey(tcell.KeyRune, r, 0))
+	}
+	assert.Equal(t, 0, ui.entriesList.GetItemCount())
+
+	ui.reloadEntries(ui.entriesURL)
+	assert.Equal(t, 1, ui.entriesList.GetItemCount())
+	assert.Equal(t, "registry three", ui.listedEntries()[0].title)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"github.com/rivo/tview"
+	"strings"
+)
+
+// folderURLPrefix stands in for a feed url on the feed list rows of folders, followed by the path of the folder
+const folderURLPrefix = "clacks://folder/"
+
+// markers in front of the names of expanded and collapsed folders in the feed list
+const expandedFolderMarker = "▾ "
+const collapsedFolderMarker = "▸ "
+
+// folderIndent indent of the rows inside a folder for each folder they are nested in
+const folderIndent = "  "
+
+// feedTreeNode a folder or a feed of the tree of feeds, folders have the feeds and folders inside them as children
+type feedTreeNode struct {
+	url      string
+	children []*feedTreeNode
+}
+
+// folderURL url of the feed list row of the folder at path
+func folderURL(path string) string {
+	return folderURLPrefix + path
+}
+
+// isFolderURL whether url is the url of a folder row
+func isFolderURL(url string) bool {
+	return strings.HasPrefix(url, folderURLPrefix)
+}
+
+// folderPath path of the folder of a folder row's url
+func folderPath(url string) string {
+	return strings.TrimPrefix(url, folderURLPrefix)
+}
+
+// folderPaths paths of the folders a category puts a feed in from the outermost folder in, so "Tech/Blogs"
+// is in the folders "Tech" and "Tech/Blogs", empty folder names are left out
+func folderPaths(category string) []string {
+	var paths []string
+	path := ""
+	for _, name := range strings.Split(category, categorySeparator) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if path != "" {
+			path += categorySeparator
+		}
+		path += name
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// parent path of the folder holding the feed or folder of a feed list row, empty when it isn't in a folder
+func (config *ConfigData) parentFolder(url string) string {
+	var paths []string
+	if isFolderURL(url) {
+		paths = folderPaths(folderPath(url))
+		paths = paths[:len(paths)-1]
+	} else if feed, ok := config.feed(url); ok {
+		paths = folderPaths(feed.Category)
+	}
+	if len(paths) == 0 {
+		return ""
+	}
+	return paths[len(paths)-1]
+}
+
+// feedTree tree of the feeds in the config grouped into folders by their category, folders and feeds are
+// in the order they first appear in the config
+func (config *ConfigData) feedTree() *feedTreeNode {
+	root := &feedTreeNode{}
+	folders := make(map[string]*feedTreeNode)
+	for _, feed := range config.enabledFeeds() {
+		parent := root
+		for _, path := range folderPaths(feed.Category) {
+			folder, ok := folders[path]
+			if !ok {
+				folder = &feedTreeNode{url: folderURL(path)}
+				folders[path] = folder
+				parent.children = append(parent.children, folder)
+			}
+			parent = folder
+		}
+		parent.children = append(parent.children, &feedTreeNode{url: feed.URL})
+	}
+	return root
+}
+
+// folderFeeds urls of the feeds in the folder at path and the folders inside it
+func (config *ConfigData) folderFeeds(path string) []string {
+	var urls []string
+	for _, feed := range config.enabledFeeds() {
+		paths := folderPaths(feed.Category)
+		if len(paths) == 0 {
+			continue
+		}
+		if category := paths[len(paths)-1]; category == path || strings.HasPrefix(category, path+categorySeparator) {
+			urls = append(urls, feed.URL)
+		}
+	}
+	return urls
+}
+
+// entries of every feed in the folder at path merged together
+func (data *Data) folderEntries(path string) []Entry {
+	var entries []Entry
+	for _, url := range data.configData.folderFeeds(path) {
+		entries = append(entries, data.feedEntries(url)...)
+	}
+	return entries
+}
+
+// urls of the feed list rows below node, the rows inside collapsed folders are left out,
+// while filtering only the rows matching the filter are listed along with the folders holding them
+func (ui *UI) feedTreeURLs(node *feedTreeNode) []string {
+	filtering := ui.filterList == ui.feedList && ui.filterQuery != ""
+	var urls []string
+	for _, child := range node.children {
+		matches := ui.matchesFilter(ui.feedList, ui.feedListText(child.url))
+		if !isFolderURL(child.url) {
+			if matches {
+				urls = append(urls, child.url)
+			}
+			continue
+		}
+
+		childURLs := ui.feedTreeURLs(child)
+		switch {
+		case filtering && (matches || len(childURLs) > 0):
+			urls = append(urls, child.url)
+			urls = append(urls, childURLs...)
+		case !filtering:
+			urls = append(urls, child.url)
+			if !ui.collapsedFolders[folderPath(child.url)] {
+				urls = append(urls, childURLs...)
+			}
+		}
+	}
+	return urls
+}
+
+// indent of the row of a feed or folder in the feed list, nested by the folders it is inside
+func (ui *UI) feedRowIndent(url string) string {
+	depth := 0
+	for parent := ui.data.configData.parentFolder(url); parent != ""; parent = ui.data.configData.parentFolder(folderURL(parent)) {
+		depth++
+	}
+	return strings.Repeat(folderIndent, depth)
+}
+
+// name of a folder row in the feed list with whether it is collapsed and the unread entries of its feeds
+func (ui *UI) folderListText(url string) string {
+	path := folderPath(url)
+	name := path[strings.LastIndex(path, categorySeparator)+1:]
+	marker := expandedFolderMarker
+	if ui.collapsedFolders[path] {
+		marker = collapsedFolderMarker
+	}
+
+	unread := 0
+	for _, feedURL := range ui.data.configData.folderFeeds(path) {
+		unread += ui.data.safeFeedData.GetEntries(feedURL).unreadCount()
+	}
+	if unread > 0 {
+		return fmt.Sprintf("%s%s (%d)", marker, tview.Escape(name), unread)
+	}
+	return marker + tview.Escape(name)
+}
+
+// collapse the folder selected in the feed list, when a feed or a collapsed folder is selected its folder is
+// selected instead, returns whether there was a folder to collapse or select
+func (ui *UI) collapseSelectedFolder() bool {
+	url := ui.getSelectedFeedURL()
+	if isFolderURL(url) && !ui.collapsedFolders[folderPath(url)] {
+		ui.setFolderCollapsed(folderPath(url), true)
+		return true
+	}
+	parent := ui.data.configData.parentFolder(url)
+	if parent == "" {
+		return false
+	}
+	if i := ui.feedRowIndex(folderURL(parent)); i >= 0 {
+		ui.feedList.SetCurrentItem(i)
+	}
+	return true
+}
+
+// expand the folder selected in the feed list, returns whether a collapsed folder was selected
+func (ui *UI) expandSelectedFolder() bool {
+	url := ui.getSelectedFeedURL()
+	if !isFolderURL(url) || !ui.collapsedFolders[folderPath(url)] {
+		return false
+	}
+	ui.setFolderCollapsed(folderPath(url), false)
+	return true
+}
+
+// collapse or expand the folder at path and list the feed rows again
+func (ui *UI) setFolderCollapsed(path string, collapsed bool) {
+	if collapsed {
+		ui.collapsedFolders[path] = true
+	} else {
+		delete(ui.collapsedFolders, path)
+	}
+	ui.populateFeedList()
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// ui of the test feeds with the registry in the folder News/Tech and google in News
+func setupFolders(t *testing.T) (*UI, func()) {
+	data := createTestData(false)
+	data.configData.Feeds[0].Category = "News/Tech"
+	data.configData.Feeds[1].Category = "News"
+	simScreen, ui := setupWithSimScreen(data)
+	ui.setInputCaptureHandler()
+	return ui, simScreen.Fini
+}
+
+// text of every row of the feed list
+func feedRows(ui *UI) []string {
+	var rows []string
+	for i := 0; i < ui.feedList.GetItemCount(); i++ {
+		text, _ := ui.feedList.GetItemText(i)
+		rows = append(rows, text)
+	}
+	return rows
+}
+
+func TestFolderPaths(t *testing.T) {
+	assert.Equal(t, []string{"Tech", "Tech/Blogs"}, folderPaths("Tech/Blogs"))
+	assert.Equal(t, []string{"Tech", "Tech/Blogs"}, folderPaths(" Tech //Blogs/"))
+	assert.Nil(t, folderPaths(""))
+
+	config := &ConfigData{Feeds: []Feed{{URL: "a", Category: "Tech/Blogs"}, {URL: "b"}, {URL: "c", Category: "Tech"},
+		{URL: "d", Category: "Technology"}}}
+	assert.Equal(t, []string{"a", "c"}, config.folderFeeds("Tech"))
+	assert.Equal(t, []string{"a"}, config.folderFeeds("Tech/Blogs"))
+	assert.Equal(t, "Tech/Blogs", config.parentFolder("a"))
+	assert.Equal(t, "Tech", config.parentFolder(folderURL("Tech/Blogs")))
+	assert.Equal(t, "", config.parentFolder("b"))
+}
+
+func TestFeedListGroupsFeedsIntoFolders(t *testing.T) {
+	ui, fini := setupFolders(t)
+	defer fini()
+
+	assert.Equal(t, []string{"All (4)", "▾ News (4)", "  ▾ Tech (2)", "    registry (2)", "  google (2)"}, feedRows(ui))
+	_, url := ui.feedList.GetItemText(1)
+	assert.Equal(t, folderURL("News"), url)
+}
+
+func TestSelectingFolderListsEntriesOfItsFeeds(t *testing.T) {
+	ui, fini := setupFolders(t)
+	defer fini()
+
+	ui.feedList.SetCurrentItem(2)
+	assert.Equal(t, folderURL("News/Tech"), ui.entriesURL)
+	assert.Equal(t, 2, ui.entriesList.GetItemCount())
+
+	ui.feedList.SetCurrentItem(1)
+	assert.Equal(t, 4, ui.entriesList.GetItemCount())
+	text, _ := ui.entriesList.GetItemText(0)
+	assert.Contains(t, visibleText(text), "registry registry fake title one")
+
+	ui.markSelectedFeedRead()
+	assert.Equal(t, []string{"All", "▾ News", "  ▾ Tech", "    registry", "  google"}, feedRows(ui))
+}
+
+func TestCollapseAndExpandFolders(t *testing.T) {
+	ui, fini := setupFolders(t)
+	defer fini()
+
+	capture := ui.app.GetInputCapture()
+	ui.app.SetFocus(ui.feedList)
+	ui.feedList.SetCurrentItem(3)
+
+	// left from a feed selects its folder, then collapses it
+	assert.Nil(t, capture(tcell.NewEventKey(tcell.KeyLeft, 0, 0)))
+	assert.Equal(t, folderURL("News/Tech"), ui.getSelectedFeedURL())
+	assert.Nil(t, capture(tcell.NewEventKey(tcell.KeyLeft, 0, 0)))
+	assert.Equal(t, []string{"All (4)", "▾ News (4)", "  ▸ Tech (2)", "  google (2)"}, feedRows(ui))
+	assert.Equal(t, folderURL("News/Tech"), ui.getSelectedFeedURL())
+
+	assert.Nil(t, capture(tcell.NewEventKey(tcell.KeyRight, 0, 0)))
+	assert.Len(t, feedRows(ui), 5)
+	event := tcell.NewEventKey(tcell.KeyRight, 0, 0)
+	assert.Equal(t, event, capture(event))
+
+	// a collapsed folder keeps the feed selected inside it selected, along with the selected entry
+	ui.feedList.SetCurrentItem(3)
+	ui.entriesList.SetCurrentItem(1)
+	ui.setFolderCollapsed("News", true)
+	assert.Equal(t, []string{"All (4)", "▸ News (4)"}, feedRows(ui))
+	assert.Equal(t, folderURL("News"), ui.getSelectedFeedURL())
+	assert.Equal(t, folderURL("News"), ui.entriesURL)
+	_, url := ui.entriesList.GetItemText(ui.entriesList.GetCurrentItem())
+	assert.Equal(t, testURLOne+"/two", url)
+
+	// feeds outside folders have nothing to collapse
+	ui.feedList.SetCurrentItem(0)
+	event = tcell.NewEventKey(tcell.KeyLeft, 0, 0)
+	assert.Equal(t, event, capture(event))
+}
+
+func TestFilterListsFoldersOfMatchingFeeds(t *testing.T) {
+	ui, fini := setupFolders(t)
+	defer fini()
+
+	ui.setFolderCollapsed("News", true)
+	ui.filterList = ui.feedList
+	ui.filterQuery = "registry"
+	ui.populateFeedList()
+
+	var urls []string
+	for i := 0; i < ui.feedList.GetItemCount(); i++ {
+		_, url := ui.feedList.GetItemText(i)
+		urls = append(urls, url)
+	}
+	assert.Equal(t, []string{folderURL("News"), folderURL("News/Tech"), testURLOne}, urls)
+}
+
+func TestUpdateFeedRowInCollapsedFolder(t *testing.T) {
+	data := createTestData(false)
+	data.configData.Feeds[0].Category = "News/Tech"
+	data.configData.Feeds[1].Category = "News"
+	app := CreateStubbedApp(true)
+	ui := CreateUI(app, data)
+	ui.setupLists()
+
+	ui.feedList.SetCurrentItem(1)
+	ui.setFolderCollapsed("News", true)
+
+	refreshed := createFakeFeedDataModel("google", testURLTwo)
+	refreshed.entries = append([]Entry{{guid: "new", title: "new entry", url: testURLTwo + "/new"}}, refreshed.entries...)
+	ui.data.safeFeedData.SetSiteData(testURLTwo, refreshed)
+	ui.updateFeedRow(testURLTwo)
+	for _, f := range app.(*StubbedApp).QueuedUpdateDraws() {
+		f()
+	}
+
+	assert.Equal(t, []string{"All (5)", "▸ News (5)"}, feedRows(ui))
+	assert.Equal(t, 5, ui.entriesList.GetItemCount())
+}
@@ -11,6 +11,7 @@ require (
 	github.com/rivo/tview v0.0.0-20210426120146-ea0971753caf
 	github.com/shirou/gopsutil v2.20.4+incompatible // indirect
 	github.com/stretchr/testify v1.6.1
+	go.etcd.io/bbolt v1.3.6
 	golang.org/x/lint v0.0.0-20210508222113-6edffad5e616 // indirect
 	golang.org/x/net v0.0.0-20210405180319-a5a99cb37ef4
 	golang.org/x/term v0.0.0-20210503060354-a79de5458b56 // indirect
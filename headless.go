@@ -0,0 +1,442 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// formats the output of a command can be written in
+const (
+	textFormat = "text"
+	jsonFormat = "json"
+	tsvFormat  = "tsv"
+)
+
+// entryIDLength number of hex digits of the ids entries are shown with
+const entryIDLength = 12
+
+// tsvEscaper escapes the characters that would break a tsv field
+var tsvEscaper = strings.NewReplacer("\\", "\\\\", "\t", "\\t", "\n", "\\n", "\r", "\\r")
+
+// feedRecord a feed as listed by the list-feeds command
+type feedRecord struct {
+	URL      string `json:"url"`
+	Name     string `json:"name"`
+	Category string `json:"category,omitempty"`
+	Entries  int    `json:"entries"`
+	Unread   int    `json:"unread"`
+}
+
+// fetchRecord what fetching a feed found
+type fetchRecord struct {
+	URL     string `json:"url"`
+	Name    string `json:"name"`
+	Entries int    `json:"entries"`
+	New     int    `json:"new"`
+	Error   string `json:"error,omitempty"`
+}
+
+// entryRecord an entry as listed by the entries command, content and text are only filled in by the show command
+type entryRecord struct {
+	ID         string   `json:"id"`
+	Feed       string   `json:"feed"`
+	FeedURL    string   `json:"feed_url"`
+	Title      string   `json:"title"`
+	URL        string   `json:"url"`
+	Author     string   `json:"author,omitempty"`
+	Date       string   `json:"date,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	Read       bool     `json:"read"`
+	Content    string   `json:"content,omitempty"`
+	Text       string   `json:"text,omitempty"`
+}
+
+// commandOutput rows printed by a command under the names of their columns, records are written instead as json
+type commandOutput struct {
+	columns []string
+	rows    [][]string
+	records interface{}
+}
+
+// write the output of a command in format, text lines the columns up and tsv escapes tabs and new lines
+func (output commandOutput) write(out io.Writer, format string) error {
+	switch format {
+	case jsonFormat:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(output.records)
+	case tsvFormat:
+		lines := []string{strings.Join(output.columns, "\t")}
+		for _, row := range output.rows {
+			fields := make([]string, len(row))
+			for i, field := range row {
+				fields[i] = tsvEscaper.Replace(field)
+			}
+			lines = append(lines, strings.Join(fields, "\t"))
+		}
+		_, err := fmt.Fprintln(out, strings.Join(lines, "\n"))
+		return err
+	default:
+		writer := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+		_, _ = fmt.Fprintln(writer, strings.ToUpper(strings.Join(output.columns, "\t")))
+		for _, row := range output.rows {
+			_, _ = fmt.Fprintln(writer, strings.Join(row, "\t"))
+		}
+		return writer.Flush()
+	}
+}
+
+// check format is one commands can write
+func checkFormat(format string) error {
+	switch format {
+	case textFormat, jsonFormat, tsvFormat:
+		return nil
+	}
+	return errors.New("error unknown format " + format + ", use text, json or tsv")
+}
+
+// newCommandFlags flags of a command, every command takes --format
+func newCommandFlags(name string) (*flag.FlagSet, *string) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	format := flags.String("format", textFormat, "output format")
+	return flags, format
+}
+
+// parse the flags of a command, flags can come before or after its arguments, returns the arguments
+func parseCommandFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	var arguments []string
+	for {
+		err := flags.Parse(args)
+		if err != nil {
+			return nil, errors.New(usage)
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			return arguments, nil
+		}
+		arguments = append(arguments, args[0])
+		args = args[1:]
+	}
+}
+
+// load the config and the entries kept in the store for a command or the terminal ui,
+// the store has to be closed once the data isn't needed
+func (controller *Controller) loadStoredData() (*Data, EntryStore, error) {
+	data := NewData(controller.feedParser)
+	data.articles = controller.articleFetcher
+	data.discoverer = controller.feedDiscoverer
+	err := data.loadJSONConfig(controller.configFileName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	store, err := OpenBoltStore(filepath.Join(controller.dataDir, storeFileName))
+	if err != nil {
+		return nil, nil, err
+	}
+	data.store = store
+	// responses are cached in the store so unchanged feeds aren't downloaded again
+	if httpFeedParser, ok := controller.feedParser.(*HTTPFeedParser); ok {
+		httpFeedParser.cache = store
+	}
+
+	err = data.loadFeedsFromStore()
+	if err != nil {
+		_ = store.Close()
+		return nil, nil, err
+	}
+	return data, store, nil
+}
+
+// list the feeds in the config with how many entries of each are stored and unread
+func (controller *Controller) listFeeds(args []string, out io.Writer) error {
+	flags, format := newCommandFlags("list-feeds")
+	arguments, err := parseCommandFlags(flags, args)
+	if err != nil || len(arguments) != 0 {
+		return errors.New(usage)
+	}
+	if err = checkFormat(*format); err != nil {
+		return err
+	}
+
+	data, store, err := controller.loadStoredData()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	output := commandOutput{columns: []string{"url", "name", "category", "entries", "unread"}}
+	records := []feedRecord{}
+	for _, feed := range data.configData.enabledFeeds() {
+		feedData := data.safeFeedData.GetEntries(feed.URL)
+		record := feedRecord{URL: feed.URL, Name: data.feedName(feed.URL), Category: feed.Category,
+			Entries: len(feedData.entries), Unread: feedData.unreadCount()}
+		records = append(records, record)
+		output.rows = append(output.rows, []string{record.URL, record.Name, record.Category,
+			strconv.Itoa(record.Entries), strconv.Itoa(record.Unread)})
+	}
+	output.records = records
+	return output.write(out, *format)
+}
+
+// fetch every feed in the config into the store, fails if any feed couldn't be fetched
+func (controller *Controller) fetch(args []string, out io.Writer) error {
+	flags, format := newCommandFlags("fetch")
+	arguments, err := parseCommandFlags(flags, args)
+	if err != nil || len(arguments) != 0 {
+		return errors.New(usage)
+	}
+	if err = checkFormat(*format); err != nil {
+		return err
+	}
+
+	data, store, err := controller.loadStoredData()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	err = data.loadDataFromFeeds(nil)
+	if err != nil {
+		return err
+	}
+
+	output := commandOutput{columns: []string{"url", "name", "entries", "new", "error"}}
+	records := []fetchRecord{}
+	failed := 0
+	for _, feed := range data.configData.enabledFeeds() {
+		feedData := data.safeFeedData.GetEntries(feed.URL)
+		record := fetchRecord{URL: feed.URL, Name: data.feedName(feed.URL), Entries: len(feedData.entries)}
+		for _, entry := range feedData.entries {
+			if entry.isNew {
+				record.New++
+			}
+		}
+		if feedData.err != nil {
+			record.Error = feedData.err.Error()
+			failed++
+		}
+		records = append(records, record)
+		output.rows = append(output.rows, []string{record.URL, record.Name, strconv.Itoa(record.Entries),
+			strconv.Itoa(record.New), record.Error})
+	}
+	output.records = records
+
+	err = output.write(out, *format)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return errors.New("error " + strconv.Itoa(failed) + " of " + strconv.Itoa(len(records)) + " feeds failed to fetch")
+	}
+	return nil
+}
+
+// list the stored entries of a feed newest first, all lists the entries of every feed
+func (controller *Controller) listEntries(args []string, out io.Writer) error {
+	flags, format := newCommandFlags("entries")
+	since := flags.String("since", "", "only entries newer than a duration such as 24h or a date")
+	unread := flags.Bool("unread", false, "only unread entries")
+	arguments, err := parseCommandFlags(flags, args)
+	if err != nil || len(arguments) != 1 {
+		return errors.New(usage)
+	}
+	if err = checkFormat(*format); err != nil {
+		return err
+	}
+	var after time.Time
+	if *since != "" {
+		after, err = parseSince(*since, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	data, store, err := controller.loadStoredData()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	var entries []Entry
+	if arguments[0] == "all" {
+		entries = data.allEntries()
+	} else {
+		url, ok := data.findFeed(arguments[0])
+		if !ok {
+			return errors.New("error no feed " + arguments[0] + " in config")
+		}
+		entries = data.feedEntries(url)
+	}
+
+	output := commandOutput{columns: []string{"id", "date", "read", "feed", "title", "url"}}
+	records := []entryRecord{}
+	for _, entry := range sortEntries(entries, newestFirst) {
+		if (*unread && entry.read) || (!after.IsZero() && !entry.date().After(after)) {
+			continue
+		}
+		record := data.entryRecord(entry)
+		records = append(records, record)
+		output.rows = append(output.rows, []string{record.ID, record.Date, strconv.FormatBool(record.Read),
+			record.Feed, record.Title, record.URL})
+	}
+	output.records = records
+	return output.write(out, *format)
+}
+
+// show a stored entry with its content as text, entries are found by their id or the start of it
+func (controller *Controller) showEntry(args []string, out io.Writer) error {
+	flags, format := newCommandFlags("show")
+	arguments, err := parseCommandFlags(flags, args)
+	if err != nil || len(arguments) != 1 {
+		return errors.New(usage)
+	}
+	if err = checkFormat(*format); err != nil {
+		return err
+	}
+
+	data, store, err := controller.loadStoredData()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	entry, err := data.findEntry(arguments[0])
+	if err != nil {
+		return err
+	}
+
+	record := data.entryRecord(entry)
+	record.Content = entry.content
+	if entry.fullContent != "" {
+		record.Content = entry.fullContent
+	}
+	rendered, _ := renderContent(record.Content, entry.url)
+	record.Text = visibleText(rendered)
+
+	switch *format {
+	case textFormat:
+		_, err = fmt.Fprintf(out, "%s\nFeed: %s\n%s\n%s\n", entry.title, record.Feed, visibleText(entryHeader(entry)), record.Text)
+		return err
+	default:
+		output := commandOutput{
+			columns: []string{"id", "date", "read", "feed", "title", "url", "author", "text"},
+			rows: [][]string{{record.ID, record.Date, strconv.FormatBool(record.Read), record.Feed, record.Title,
+				record.URL, record.Author, record.Text}},
+			records: record,
+		}
+		return output.write(out, *format)
+	}
+}
+
+// list the feeds found on a web site, the url can be the site or one of its pages
+func (controller *Controller) discover(args []string, out io.Writer) error {
+	flags, format := newCommandFlags("discover")
+	arguments, err := parseCommandFlags(flags, args)
+	if err != nil || len(arguments) != 1 {
+		return errors.New(usage)
+	}
+	if err = checkFormat(*format); err != nil {
+		return err
+	}
+
+	feeds, err := controller.feedDiscoverer.DiscoverFeeds(arguments[0])
+	if err != nil {
+		return errors.New("error discovering feeds: " + err.Error())
+	}
+
+	output := commandOutput{columns: []string{"url", "title"}, records: feeds}
+	for _, feed := range feeds {
+		output.rows = append(output.rows, []string{feed.URL, feed.Title})
+	}
+	return output.write(out, *format)
+}
+
+// parseSince time entries have to be newer than, either a duration before now such as 24h or a date
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if date, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, errors.New("error --since must be a duration such as 24h or a date such as 2021-05-03")
+}
+
+// entryID short id of an entry that stays the same between runs
+func entryID(feedURL, guid string) string {
+	sum := sha1.Sum([]byte(feedURL + "\x00" + guid))
+	return hex.EncodeToString(sum[:])[:entryIDLength]
+}
+
+// name of a feed, the name given to it in the config or the title of the feed, its url until it has been fetched
+func (data *Data) feedName(url string) string {
+	if feed, ok := data.configData.feed(url); ok && feed.Name != "" {
+		return feed.Name
+	}
+	name := data.safeFeedData.GetEntries(url).name
+	if name == "" {
+		return url
+	}
+	return name
+}
+
+// findFeed url of the feed in the config with a url or name matching feed
+func (data *Data) findFeed(feed string) (string, bool) {
+	for _, configFeed := range data.configData.enabledFeeds() {
+		if configFeed.URL == feed || strings.EqualFold(configFeed.Name, feed) ||
+			strings.EqualFold(data.safeFeedData.GetEntries(configFeed.URL).name, feed) {
+			return configFeed.URL, true
+		}
+	}
+	return "", false
+}
+
+// findEntry entry of any feed in the config with an id starting with id
+func (data *Data) findEntry(id string) (Entry, error) {
+	var found []Entry
+	for _, entry := range data.allEntries() {
+		if strings.HasPrefix(entryID(entry.feedURL, entry.guid), strings.ToLower(id)) {
+			found = append(found, entry)
+		}
+	}
+	switch {
+	case id == "" || len(found) == 0:
+		return Entry{}, errors.New("error no entry with id " + id)
+	case len(found) > 1:
+		return Entry{}, errors.New("error more than one entry has an id starting with " + id)
+	}
+	return found[0], nil
+}
+
+// entryRecord entry as it is written out by the entries and show commands
+func (data *Data) entryRecord(entry Entry) entryRecord {
+	record := entryRecord{
+		ID:         entryID(entry.feedURL, entry.guid),
+		Feed:       data.feedName(entry.feedURL),
+		FeedURL:    entry.feedURL,
+		Title:      entry.title,
+		URL:        entry.url,
+		Author:     entry.author,
+		Categories: entry.categories,
+		Read:       entry.read,
+	}
+	if !entry.date().IsZero() {
+		record.Date = entry.date().Format(time.RFC3339)
+	}
+	return record
+}
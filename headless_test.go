@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testHeadlessConfig = `{"feeds": [
+  {"url": "theregistry.com", "name": "Registry", "category": "News"},
+  {"url": "google.com"}
+]}`
+
+// controller with a config of two feeds and an empty entry store in a temp dir, feeds are fetched from parser
+func createHeadlessController(t *testing.T, parser FeedParser) *Controller {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, configFileName)
+	assert.Nil(t, ioutil.WriteFile(configFile, []byte(testHeadlessConfig), 0600))
+	return &Controller{feedParser: parser, configFileName: configFile, dataDir: dir}
+}
+
+// run a command and return what it wrote
+func runHeadless(t *testing.T, controller *Controller, args ...string) (string, error) {
+	var out bytes.Buffer
+	err := controller.runCommand(args, &out)
+	return out.String(), err
+}
+
+func TestFetchStoresFeeds(t *testing.T) {
+	fakeFeed := CreateTestFeed()
+	controller := createHeadlessController(t, createStubbedParser(&fakeFeed, false))
+
+	out, err := runHeadless(t, controller, "fetch", "--format", "tsv")
+	assert.Nil(t, err)
+	assert.Equal(t, "url\tname\tentries\tnew\terror\n"+
+		"theregistry.com\tRegistry\t2\t0\t\n"+
+		"google.com\tTest Feed Title From Parser\t2\t0\t\n", out)
+
+	out, err = runHeadless(t, controller, "list-feeds")
+	assert.Nil(t, err)
+	assert.Equal(t, "URL              NAME                         CATEGORY  ENTRIES  UNREAD\n"+
+		"theregistry.com  Registry                     News      2        2\n"+
+		"google.com       Test Feed Title From Parser            2        2\n", out)
+}
+
+func TestFetchFailsWhenAFeedFails(t *testing.T) {
+	fakeFeed := CreateTestFeed()
+	parser := createStubbedParser(&fakeFeed, false).(*StubbedParser)
+	parser.failingURLs = map[string]bool{testURLTwo: true}
+	controller := createHeadlessController(t, parser)
+
+	out, err := runHeadless(t, controller, "fetch", "--format", "json")
+	assert.NotNil(t, err)
+	assert.Equal(t, "error 1 of 2 feeds failed to fetch", err.Error())
+
+	var records []fetchRecord
+	assert.Nil(t, json.Unmarshal([]byte(out), &records))
+	assert.Equal(t, []fetchRecord{
+		{URL: testURLOne, Name: "Registry", Entries: 2},
+		{URL: testURLTwo, Name: testURLTwo, Error: "error loading feed: stubbed parser error"},
+	}, records)
+}
+
+func TestListFeedsBeforeFetching(t *testing.T) {
+	controller := createHeadlessController(t, nil)
+
+	out, err := runHeadless(t, controller, "list-feeds", "--format=json")
+	assert.Nil(t, err)
+
+	var records []feedRecord
+	assert.Nil(t, json.Unmarshal([]byte(out), &records))
+	assert.Equal(t, []feedRecord{{URL: testURLOne, Name: "Registry", Category: "News"}, {URL: testURLTwo, Name: testURLTwo}}, records)
+}
+
+func TestEntriesAndShow(t *testing.T) {
+	controller := createHeadlessController(t, nil)
+	store, err := OpenBoltStore(filepath.Join(controller.dataDir, storeFileName))
+	assert.Nil(t, err)
+	now := time.Now()
+	_, err = store.MergeFeed(testURLOne, FeedDataModel{name: "The Register", entries: []Entry{
+		{guid: "old", title: "Old news", url: "https://example.com/old", published: now.Add(-72 * time.Hour)},
+		{guid: "new", title: "New news", url: "https://example.com/new", published: now.Add(-time.Hour),
+			author: "Simon", content: "<p>Fresh <b>news</b> and <a href='/more'>more</a></p>"},
+	}})
+	assert.Nil(t, err)
+	assert.Nil(t, store.SetRead(testURLOne, []string{"old"}, true))
+	assert.Nil(t, store.Close())
+
+	newID, oldID := entryID(testURLOne, "new"), entryID(testURLOne, "old")
+	assert.Len(t, newID, entryIDLength)
+	assert.NotEqual(t, newID, oldID)
+
+	out, err := runHeadless(t, controller, "entries", "Registry", "--format", "json")
+	assert.Nil(t, err)
+	var records []entryRecord
+	assert.Nil(t, json.Unmarshal([]byte(out), &records))
+	assert.Len(t, records, 2)
+	assert.Equal(t, newID, records[0].ID)
+	assert.Equal(t, "Registry", records[0].Feed)
+	assert.Equal(t, now.Add(-time.Hour).Format(time.RFC3339), records[0].Date)
+	assert.Equal(t, oldID, records[1].ID)
+	assert.True(t, records[1].Read)
+
+	out, err = runHeadless(t, controller, "entries", "--unread", "--format", "tsv", testURLOne)
+	assert.Nil(t, err)
+	assert.Equal(t, "id\tdate\tread\tfeed\ttitle\turl\n"+
+		newID+"\t"+records[0].Date+"\tfalse\tRegistry\tNew news\thttps://example.com/new\n", out)
+
+	out, err = runHeadless(t, controller, "entries", "all", "--since", "24h", "--format", "tsv")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, strings.Count(out, "\n"))
+	assert.Contains(t, out, "New news")
+
+	out, err = runHeadless(t, controller, "show", newID[:6])
+	assert.Nil(t, err)
+	assert.Contains(t, out, "New news\nFeed: Registry\nAuthor: Simon\n")
+	assert.Contains(t, out, "Link: https://example.com/new\n")
+	assert.Contains(t, out, "Fresh news and more[1]\n\nLinks:\n[1] https://example.com/more\n")
+
+	out, err = runHeadless(t, controller, "show", oldID, "--format", "json")
+	assert.Nil(t, err)
+	var record entryRecord
+	assert.Nil(t, json.Unmarshal([]byte(out), &record))
+	assert.Equal(t, "Old news", record.Title)
+}
+
+func TestEntriesAndShowErrors(t *testing.T) {
+	controller := createHeadlessController(t, nil)
+
+	_, err := runHeadless(t, controller, "entries", "missing")
+	assert.Equal(t, "error no feed missing in config", err.Error())
+
+	_, err = runHeadless(t, controller, "entries")
+	assert.Equal(t, usage, err.Error())
+
+	_, err = runHeadless(t, controller, "entries", "all", "--since", "yesterday")
+	assert.Equal(t, "error --since must be a duration such as 24h or a date such as 2021-05-03", err.Error())
+
+	_, err = runHeadless(t, controller, "list-feeds", "--format", "xml")
+	assert.Equal(t, "error unknown format xml, use text, json or tsv", err.Error())
+
+	_, err = runHeadless(t, controller, "show", "abc")
+	assert.Equal(t, "error no entry with id abc", err.Error())
+
+	_, err = runHeadless(t, controller, "show", "--bogus", "abc")
+	assert.Equal(t, usage, err.Error())
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2021, time.May, 3, 12, 0, 0, 0, time.UTC)
+
+	since, err := parseSince("36h", now)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2021, time.May, 2, 0, 0, 0, 0, time.UTC), since)
+
+	since, err = parseSince("2021-04-01T10:00:00Z", now)
+	assert.Nil(t, err)
+	assert.True(t, time.Date(2021, time.April, 1, 10, 0, 0, 0, time.UTC).Equal(since))
+
+	since, err = parseSince("2021-04-01", now)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2021, time.April, 1, 0, 0, 0, 0, time.Local), since)
+}
+
+func TestCommandOutputEscapesTSV(t *testing.T) {
+	var out bytes.Buffer
+	output := commandOutput{columns: []string{"a", "b"}, rows: [][]string{{"tab\there", "new\nline"}}}
+
+	assert.Nil(t, output.write(&out, tsvFormat))
+	assert.Equal(t, "a\tb\ntab\\there\tnew\\nline\n", out.String())
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"github.com/gdamore/tcell/v2"
+	"strings"
+	"unicode/utf8"
+)
+
+// names of the actions keys can be bound to in the keys section of the config
+const (
+	actionUp             = "up"
+	actionDown           = "down"
+	actionSelect         = "select"
+	actionBack           = "back"
+	actionNextFeed       = "next-feed"
+	actionPreviousFeed   = "previous-feed"
+	actionNextEntry      = "next-entry"
+	actionPreviousEntry  = "previous-entry"
+	actionOpen           = "open-in-browser"
+	actionLinks          = "links"
+	actionReader         = "reader-mode"
+	actionCollapseFolder = "collapse-folder"
+	actionExpandFolder   = "expand-folder"
+	actionAddFeed        = "add-feed"
+	actionRenameFeed     = "rename-feed"
+	actionDeleteFeed     = "delete-feed"
+	actionToggleRead     = "toggle-read"
+	actionMarkAllRead    = "mark-all-read"
+	actionSort           = "sort"
+	actionSearch         = "search"
+	actionFilter         = "filter"
+	actionRefresh        = "refresh"
+	actionHelp           = "help"
+	actionQuit           = "quit"
+)
+
+// keyAction an action keys can be bound to, its description in the help page and the keys bound by default
+type keyAction struct {
+	name        string
+	description string
+	defaultKeys []string
+}
+
+// keyActions every action in the order they are listed in the help page
+var keyActions = []keyAction{
+	{actionUp, "move up the focused list", []string{"Up"}},
+	{actionDown, "move down the focused list", []string{"Down"}},
+	{actionSelect, "move from a feed to its entries or open an entry in your browser", []string{"Enter"}},
+	{actionBack, "move from the entries back to the feeds", []string{"Esc"}},
+	{actionNextFeed, "select the next feed", []string{"]"}},
+	{actionPreviousFeed, "select the previous feed", []string{"["}},
+	{actionNextEntry, "select the next entry", []string{"n"}},
+	{actionPreviousEntry, "select the previous entry", []string{"p"}},
+	{actionOpen, "open the selected entry in your browser", []string{"o"}},
+	{actionLinks, "choose a link of the entry to open in your browser", []string{"l"}},
+	{actionReader, "fetch the full article of the entry from its web page", []string{"m"}},
+	{actionCollapseFolder, "collapse the selected folder of feeds or select the folder of the selected feed", []string{"Left"}},
+	{actionExpandFolder, "expand the selected folder of feeds", []string{"Right"}},
+	{actionAddFeed, "add a feed to feeds.json", []string{"+"}},
+	{actionRenameFeed, "rename the selected feed", []string{"e"}},
+	{actionDeleteFeed, "remove the selected feed from feeds.json", []string{"d"}},
+	{actionToggleRead, "toggle an entry between read and unread", []string{"u"}},
+	{actionMarkAllRead, "mark every entry in the entries list read", []string{"a"}},
+	{actionSort, "sort entries newest first, oldest first or in publisher order", []string{"s"}},
+	{actionSearch, "search the titles and content of every entry", []string{"/"}},
+	{actionFilter, "filter the focused list by typing, Esc shows the whole list again", []string{"f"}},
+	{actionRefresh, "refresh feeds", []string{"r"}},
+	{actionHelp, "show this help", []string{"h"}},
+	{actionQuit, "quit", []string{"q"}},
+}
+
+// Keymap keys bound to each action
+type Keymap struct {
+	actions map[string]string
+	keys    map[string][]string
+}
+
+// newKeymap keymap of the default bindings with the keys of any action in bindings replaced,
+// keys bound in bindings are taken away from the actions they are bound to by default
+func newKeymap(bindings map[string][]string) (*Keymap, error) {
+	keymap := &Keymap{actions: make(map[string]string), keys: make(map[string][]string)}
+
+	known := make(map[string]bool, len(keyActions))
+	for _, action := range keyActions {
+		known[action.name] = true
+	}
+
+	rebound := make(map[string]bool)
+	for action, keys := range bindings {
+		if !known[action] {
+			return nil, errors.New("error unknown action " + action + " in keys config")
+		}
+		var canonicalKeys []string
+		for _, name := range keys {
+			key, err := canonicalKeyName(name)
+			if err != nil {
+				return nil, err
+			}
+			if other, ok := keymap.actions[key]; ok && other != action {
+				return nil, errors.New("error key " + key + " is bound to both " + other + " and " + action)
+			}
+			keymap.actions[key] = action
+			rebound[key] = true
+			canonicalKeys = append(canonicalKeys, key)
+		}
+		keymap.keys[action] = canonicalKeys
+	}
+
+	for _, action := range keyActions {
+		if _, ok := bindings[action.name]; ok {
+			continue
+		}
+		for _, key := range action.defaultKeys {
+			if !rebound[key] {
+				keymap.actions[key] = action.name
+				keymap.keys[action.name] = append(keymap.keys[action.name], key)
+			}
+		}
+	}
+	return keymap, nil
+}
+
+// action bound to the key of event, empty when the key isn't bound
+func (keymap *Keymap) action(event *tcell.EventKey) string {
+	return keymap.actions[keyName(event)]
+}
+
+// keysFor keys bound to action
+func (keymap *Keymap) keysFor(action string) []string {
+	return keymap.keys[action]
+}
+
+// name of the key of event, keys that type a character are named by it and other keys by tcell's names for them
+func keyName(event *tcell.EventKey) string {
+	if event.Key() == tcell.KeyRune {
+		return string(event.Rune())
+	}
+	return tcell.KeyNames[event.Key()]
+}
+
+// canonicalKeyName name of a key named in the config as keyName names it, the names of keys that don't
+// type a character can be written in any case and the space bar is named Space
+func canonicalKeyName(name string) (string, error) {
+	if utf8.RuneCountInString(name) == 1 {
+		return name, nil
+	}
+	if strings.EqualFold(name, "Space") {
+		return " ", nil
+	}
+	for _, keyName := range tcell.KeyNames {
+		if strings.EqualFold(keyName, name) {
+			return keyName, nil
+		}
+	}
+	return "", errors.New("error unknown key " + name + " in keys config")
+}
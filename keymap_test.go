@@ -0,0 +1,68 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDefaultKeymap(t *testing.T) {
+	keymap, err := newKeymap(nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, actionQuit, keymap.action(tcell.NewEventKey(tcell.KeyRune, 'q', 0)))
+	assert.Equal(t, actionSelect, keymap.action(tcell.NewEventKey(tcell.KeyEnter, 0, 0)))
+	assert.Equal(t, "", keymap.action(tcell.NewEventKey(tcell.KeyRune, 'z', 0)))
+	assert.Equal(t, []string{"r"}, keymap.keysFor(actionRefresh))
+}
+
+func TestKeymapRebindsActions(t *testing.T) {
+	keymap, err := newKeymap(map[string][]string{
+		actionQuit:      {"x", "ctrl-q"},
+		actionNextEntry: {"j", "q"},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"x", "Ctrl-Q"}, keymap.keysFor(actionQuit))
+	assert.Equal(t, actionQuit, keymap.action(tcell.NewEventKey(tcell.KeyCtrlQ, 0, tcell.ModCtrl)))
+	assert.Equal(t, actionNextEntry, keymap.action(tcell.NewEventKey(tcell.KeyRune, 'q', 0)))
+	assert.Equal(t, "", keymap.action(tcell.NewEventKey(tcell.KeyRune, 'n', 0)))
+	assert.Equal(t, actionHelp, keymap.action(tcell.NewEventKey(tcell.KeyRune, 'h', 0)))
+}
+
+func TestKeymapWithBadBindings(t *testing.T) {
+	_, err := newKeymap(map[string][]string{actionQuit: {"Hyper-Q"}})
+	assert.Equal(t, "error unknown key Hyper-Q in keys config", err.Error())
+
+	_, err = newKeymap(map[string][]string{actionQuit: {"x"}, actionHelp: {"x"}})
+	assert.Contains(t, err.Error(), "error key x is bound to both")
+}
+
+func TestReboundKeysDriveTheUI(t *testing.T) {
+	data := createTestData(false)
+	data.configData.Keys = map[string][]string{actionDown: {"j"}, actionSelect: {"l"}, actionHelp: {"?"}, actionQuit: {"x"}}
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+	ui.setInputCaptureHandler()
+	capture := ui.app.GetInputCapture()
+
+	event := capture(tcell.NewEventKey(tcell.KeyRune, 'j', 0))
+	assert.Equal(t, tcell.KeyDown, event.Key())
+	event = capture(tcell.NewEventKey(tcell.KeyRune, 'l', 0))
+	assert.Equal(t, tcell.KeyEnter, event.Key())
+
+	assert.Nil(t, capture(tcell.NewEventKey(tcell.KeyRune, ']', 0)))
+	assert.Equal(t, 1, ui.feedList.GetCurrentItem())
+	assert.Nil(t, capture(tcell.NewEventKey(tcell.KeyRune, 'n', 0)))
+	assert.Equal(t, 1, ui.entriesList.GetCurrentItem())
+
+	assert.Contains(t, ui.menuTextView.GetText(true), "(?) Help")
+	capture(tcell.NewEventKey(tcell.KeyRune, '?', 0))
+	frontPage, helpModal := ui.pages.GetFrontPage()
+	assert.Equal(t, helpPage, frontPage)
+	helpModal.Draw(simScreen)
+	assert.Contains(t, getScreenContents(simScreen), "j to move down the focused list")
+	assert.Contains(t, getScreenContents(simScreen), "? to show this help")
+	assert.Contains(t, getScreenContents(simScreen), "x to exit")
+	assert.NotContains(t, getScreenContents(simScreen), "Ctrl-C")
+}
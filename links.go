@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// maxLinksHeight most rows of the link chooser shown before it scrolls
+const maxLinksHeight = 20
+
+// linkNumber number of the link a number key opens, links are numbered from 1 so 0 isn't one
+func linkNumber(event *tcell.EventKey) (int, bool) {
+	r := event.Rune()
+	if event.Key() != tcell.KeyRune || r < '1' || r > '9' {
+		return 0, false
+	}
+	return int(r - '0'), true
+}
+
+// showingEntry whether an entry is being read, its links can only be opened from the entries list or description
+func (ui *UI) showingEntry() bool {
+	focus := ui.app.GetFocus()
+	return focus == ui.entriesList || focus == ui.entryTextView
+}
+
+// open the i'th link of the entry shown in the description in the user's browser, a browser that can't be
+// launched is reported without quitting
+func (ui *UI) openEntryLink(i int) {
+	if i < 0 || i >= len(ui.entryLinks) {
+		return
+	}
+	err := ui.browserLauncher.OpenDefault(ui.entryLinks[i])
+	if err != nil {
+		ui.createMessagePage("error opening link: " + err.Error())
+	}
+}
+
+// create a list of the links of the entry shown to choose one to open in the browser
+func (ui *UI) createLinksPage() *tview.List {
+	if !ui.showingEntry() {
+		return nil
+	}
+	if len(ui.entryLinks) == 0 {
+		ui.createMessagePage("This entry has no links")
+		return nil
+	}
+	ui.previousFocus = ui.app.GetFocus()
+
+	closeLinks := func() {
+		ui.pages.SwitchToPage(feedPage)
+		ui.pages.RemovePage(linksPage)
+		ui.app.SetFocus(ui.previousFocus)
+	}
+
+	linkList := tview.NewList().ShowSecondaryText(false)
+	linkList.SetBorder(true).SetTitle("Links")
+	linkList.SetSelectedTextColor(color(ui.theme.SelectedText)).SetSelectedBackgroundColor(color(ui.theme.SelectedBackground))
+	for i, link := range ui.entryLinks {
+		linkList.AddItem(tview.Escape(fmt.Sprintf("[%d] %s", i+1, link)), "", 0, nil)
+	}
+	linkList.SetSelectedFunc(func(i int, _ string, _ string, _ rune) {
+		closeLinks()
+		ui.openEntryLink(i)
+	})
+	linkList.SetDoneFunc(closeLinks)
+	// number keys open their link straight away like they do outside the chooser once no more digits could make
+	// the number of another link, until then the link typed so far is selected so Enter opens it
+	typed := 0
+	linkList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		r := event.Rune()
+		if event.Key() != tcell.KeyRune || r < '0' || r > '9' {
+			typed = 0
+			return event
+		}
+		number := typed*10 + int(r-'0')
+		typed = 0
+		switch {
+		case number == 0 || number > len(ui.entryLinks):
+		case number*10 > len(ui.entryLinks):
+			closeLinks()
+			ui.openEntryLink(number - 1)
+		default:
+			typed = number
+			linkList.SetCurrentItem(number - 1)
+		}
+		return nil
+	})
+
+	height := len(ui.entryLinks) + 2
+	if height > maxLinksHeight {
+		height = maxLinksHeight
+	}
+	// center the chooser over the feed page
+	chooser := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(linkList, height, 0, true).
+			AddItem(nil, 0, 1, false), 0, 3, true).
+		AddItem(nil, 0, 1, false)
+	ui.pages.AddPage(linksPage, chooser, true, true)
+	ui.app.SetFocus(linkList)
+
+	return linkList
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func setupEntryWithLinks(data *Data) (tcell.SimulationScreen, *UI, *RecordingBrowserLauncher) {
+	feedDataModel := data.safeFeedData.GetEntries(testURLOne)
+	feedDataModel.entries[0].url = "https://example.com/posts/1"
+	feedDataModel.entries[0].content = `<p><a href="/first">first</a> and <a href="https://other.org/">second</a></p>`
+	data.safeFeedData.SetSiteData(testURLOne, feedDataModel)
+
+	simScreen, ui := setupWithSimScreen(data)
+	launcher := &RecordingBrowserLauncher{}
+	ui.browserLauncher = launcher
+	ui.setInputCaptureHandler()
+
+	ui.feedList.SetCurrentItem(1)
+	ui.loadEntriesIntoList(testURLOne)
+	ui.loadEntryTextView(0)
+	ui.app.SetFocus(ui.entryTextView)
+	return simScreen, ui, launcher
+}
+
+func TestNumberKeysOpenLinksOfEntry(t *testing.T) {
+	simScreen, ui, launcher := setupEntryWithLinks(createTestData(false))
+	defer simScreen.Fini()
+
+	assert.Equal(t, []string{"https://example.com/first", "https://other.org/"}, ui.entryLinks)
+	assert.Contains(t, ui.entryTextView.GetText(true), "[1] https://example.com/first")
+
+	capture := ui.app.GetInputCapture()
+	assert.Nil(t, capture(tcell.NewEventKey(tcell.KeyRune, '2', 0)))
+	assert.Nil(t, capture(tcell.NewEventKey(tcell.KeyRune, '1', 0)))
+	assert.Nil(t, capture(tcell.NewEventKey(tcell.KeyRune, '3', 0)))
+	assert.Equal(t, []string{"https://other.org/", "https://example.com/first"}, launcher.opened)
+
+	// the feed list isn't showing an entry so number keys are passed on
+	ui.app.SetFocus(ui.feedList)
+	event := tcell.NewEventKey(tcell.KeyRune, '1', 0)
+	assert.Equal(t, event, capture(event))
+	assert.Len(t, launcher.opened, 2)
+}
+
+func TestOpenEntryLinkReportsBrowserFailure(t *testing.T) {
+	simScreen, ui, _ := setupEntryWithLinks(createTestData(false))
+	defer simScreen.Fini()
+	ui.browserLauncher = StubbedBrowserLauncher{withError: true}
+
+	assert.NotPanics(t, func() {
+		ui.app.GetInputCapture()(tcell.NewEventKey(tcell.KeyRune, '1', 0))
+	})
+
+	frontPage, _ := ui.pages.GetFrontPage()
+	assert.Equal(t, messagePage, frontPage)
+	button, ok := ui.app.GetFocus().(*tview.Button)
+	assert.True(t, ok)
+	button.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, rune(0), 0), nil)
+	assert.Equal(t, ui.entryTextView, ui.app.GetFocus())
+}
+
+func TestNumberKeysFollowLeaderInVimMode(t *testing.T) {
+	data := createTestData(false)
+	data.configData.VimMode = true
+	simScreen, ui, launcher := setupEntryWithLinks(data)
+	defer simScreen.Fini()
+
+	capture := ui.app.GetInputCapture()
+	capture(tcell.NewEventKey(tcell.KeyRune, '2', 0))
+	assert.Empty(t, launcher.opened)
+
+	capture(tcell.NewEventKey(tcell.KeyRune, ' ', 0))
+	capture(tcell.NewEventKey(tcell.KeyRune, '2', 0))
+	assert.Equal(t, []string{"https://other.org/"}, launcher.opened)
+}
+
+func TestLinkChooserOpensChosenLink(t *testing.T) {
+	simScreen, ui, launcher := setupEntryWithLinks(createTestData(false))
+	defer simScreen.Fini()
+
+	capture := ui.app.GetInputCapture()
+	assert.Nil(t, capture(tcell.NewEventKey(tcell.KeyRune, 'l', 0)))
+
+	frontPage, _ := ui.pages.GetFrontPage()
+	assert.Equal(t, linksPage, frontPage)
+	linkList, ok := ui.app.GetFocus().(*tview.List)
+	assert.True(t, ok)
+	assert.Equal(t, 2, linkList.GetItemCount())
+
+	// keys typed into the chooser aren't shortcuts
+	event := tcell.NewEventKey(tcell.KeyDown, 0, 0)
+	assert.Equal(t, event, capture(event))
+	linkList.InputHandler()(event, nil)
+	linkList.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, 0), nil)
+
+	assert.Equal(t, []string{"https://other.org/"}, launcher.opened)
+	frontPage, _ = ui.pages.GetFrontPage()
+	assert.Equal(t, feedPage, frontPage)
+	assert.Equal(t, ui.entryTextView, ui.app.GetFocus())
+}
+
+func TestLinkChooserNumberKeysAndEscape(t *testing.T) {
+	simScreen, ui, launcher := setupEntryWithLinks(createTestData(false))
+	defer simScreen.Fini()
+
+	linkList := ui.createLinksPage()
+	linkList.InputHandler()(tcell.NewEventKey(tcell.KeyEscape, 0, 0), nil)
+	assert.False(t, ui.pages.HasPage(linksPage))
+	assert.Empty(t, launcher.opened)
+
+	linkList = ui.createLinksPage()
+	linkList.GetInputCapture()(tcell.NewEventKey(tcell.KeyRune, '1', 0))
+	assert.False(t, ui.pages.HasPage(linksPage))
+	assert.Equal(t, []string{"https://example.com/first"}, launcher.opened)
+}
+
+func TestLinkChooserOpensLinksPastNine(t *testing.T) {
+	simScreen, ui, launcher := setupEntryWithLinks(createTestData(false))
+	defer simScreen.Fini()
+	ui.entryLinks = nil
+	for i := 1; i <= 12; i++ {
+		ui.entryLinks = append(ui.entryLinks, fmt.Sprintf("https://example.com/%d", i))
+	}
+	typeKeys := func(linkList *tview.List, keys string) {
+		for _, r := range keys {
+			linkList.GetInputCapture()(tcell.NewEventKey(tcell.KeyRune, r, 0))
+		}
+	}
+
+	// a number more digits could follow waits for them with its link selected
+	linkList := ui.createLinksPage()
+	typeKeys(linkList, "1")
+	assert.True(t, ui.pages.HasPage(linksPage))
+	assert.Equal(t, 0, linkList.GetCurrentItem())
+	typeKeys(linkList, "2")
+	assert.False(t, ui.pages.HasPage(linksPage))
+	assert.Equal(t, []string{"https://example.com/12"}, launcher.opened)
+
+	// links without one past them open straight away, numbers past the last link are ignored
+	linkList = ui.createLinksPage()
+	typeKeys(linkList, "013")
+	assert.True(t, ui.pages.HasPage(linksPage))
+	typeKeys(linkList, "5")
+	assert.Equal(t, []string{"https://example.com/12", "https://example.com/5"}, launcher.opened)
+
+	linkList = ui.createLinksPage()
+	typeKeys(linkList, "1")
+	event := tcell.NewEventKey(tcell.KeyEnter, 0, 0)
+	assert.Equal(t, event, linkList.GetInputCapture()(event))
+	linkList.InputHandler()(event, nil)
+	assert.Equal(t, []string{"https://example.com/12", "https://example.com/5", "https://example.com/1"}, launcher.opened)
+}
+
+func TestLinkChooserWithoutLinksShowsMessage(t *testing.T) {
+	data := createTestData(false)
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+
+	ui.app.SetFocus(ui.entriesList)
+	ui.loadEntryTextView(0)
+
+	assert.Nil(t, ui.createLinksPage())
+	frontPage, _ := ui.pages.GetFrontPage()
+	assert.Equal(t, messagePage, frontPage)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+const opmlTitle = "Clacks Subscriptions"
+
+// categorySeparator joins the names of nested opml outlines into a single feed category
+const categorySeparator = "/"
+
+// opmlDocument struct to marshall OPML 2.0 subscription lists
+type opmlDocument struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Title   string   `xml:"head>title"`
+	Body    opmlBody `xml:"body"`
+}
+
+// opmlBody struct holding the top level outlines of an opml document
+type opmlBody struct {
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// opmlOutline struct describing either a feed, when it has an xmlUrl, or a folder of outlines
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string        `xml:"htmlUrl,attr,omitempty"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// parseOPML read the feeds out of an opml document, nested outlines become the category of their feeds
+func parseOPML(r io.Reader) (*ConfigData, error) {
+	var document opmlDocument
+	err := xml.NewDecoder(r).Decode(&document)
+	if err != nil {
+		return nil, errors.New("error reading opml file: " + err.Error())
+	}
+
+	config := &ConfigData{}
+	addOutlineFeeds(config, document.Body.Outlines, "")
+	if len(config.Feeds) == 0 {
+		return nil, errors.New("error opml file contains no feeds")
+	}
+	return config, nil
+}
+
+// walk outlines adding every one with a feed url to config
+func addOutlineFeeds(config *ConfigData, outlines []opmlOutline, category string) {
+	for _, outline := range outlines {
+		name := outline.Title
+		if name == "" {
+			name = outline.Text
+		}
+
+		if outline.XMLURL != "" {
+			config.Feeds = append(config.Feeds, Feed{URL: outline.XMLURL, Name: name, Category: category})
+			continue
+		}
+
+		folder := name
+		if category != "" {
+			folder = category + categorySeparator + name
+		}
+		addOutlineFeeds(config, outline.Outlines, folder)
+	}
+}
+
+// writeOPML write the feeds in config as an opml document, categories become nested outlines
+func writeOPML(w io.Writer, config *ConfigData) error {
+	document := opmlDocument{Version: "2.0", Title: opmlTitle}
+
+	for _, feed := range config.Feeds {
+		outlines := &document.Body.Outlines
+		if feed.Category != "" {
+			for _, folder := range strings.Split(feed.Category, categorySeparator) {
+				outlines = &findOrAddFolder(outlines, folder).Outlines
+			}
+		}
+
+		name := feed.Name
+		if name == "" {
+			name = feed.URL
+		}
+		*outlines = append(*outlines, opmlOutline{Text: name, Title: name, Type: "rss", XMLURL: feed.URL})
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(document)
+	if err != nil {
+		return errors.New("error writing opml: " + err.Error())
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// find the folder outline with name, adding it to the end of outlines if there isn't one
+func findOrAddFolder(outlines *[]opmlOutline, name string) *opmlOutline {
+	for i := range *outlines {
+		if (*outlines)[i].XMLURL == "" && (*outlines)[i].Text == name {
+			return &(*outlines)[i]
+		}
+	}
+	*outlines = append(*outlines, opmlOutline{Text: name, Title: name})
+	return &(*outlines)[len(*outlines)-1]
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const testOPML = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head><title>Exported Subscriptions</title></head>
+  <body>
+    <outline text="BOFH" title="Bastard Operator From Hell" type="rss" xmlUrl="https://www.theregister.com/offbeat/bofh/headlines.atom"/>
+    <outline text="Tech">
+      <outline text="Boing Boing" type="rss" xmlUrl="https://boingboing.net/feed/atom"/>
+      <outline text="Blogs" title="Blogs">
+        <outline text="Barry" type="rss" xmlUrl="https://barryodriscoll.net/feed/atom/"/>
+      </outline>
+    </outline>
+  </body>
+</opml>`
+
+func TestParseOPMLMapsNestedOutlinesToCategories(t *testing.T) {
+	config, err := parseOPML(strings.NewReader(testOPML))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []Feed{
+		{URL: "https://www.theregister.com/offbeat/bofh/headlines.atom", Name: "Bastard Operator From Hell"},
+		{URL: "https://boingboing.net/feed/atom", Name: "Boing Boing", Category: "Tech"},
+		{URL: "https://barryodriscoll.net/feed/atom/", Name: "Barry", Category: "Tech/Blogs"},
+	}, config.Feeds)
+}
+
+func TestParseOPMLWithMalformedFile(t *testing.T) {
+	config, err := parseOPML(strings.NewReader("<opml><body><outline"))
+
+	assert.Nil(t, config)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error reading opml file")
+}
+
+func TestParseOPMLWithNoFeeds(t *testing.T) {
+	config, err := parseOPML(strings.NewReader(`<opml version="2.0"><body><outline text="Empty"/></body></opml>`))
+
+	assert.Nil(t, config)
+	assert.Equal(t, "error opml file contains no feeds", err.Error())
+}
+
+func TestWriteOPMLRoundTrip(t *testing.T) {
+	config, err := parseOPML(strings.NewReader(testOPML))
+	assert.Nil(t, err)
+	config.Feeds = append(config.Feeds, Feed{URL: "https://lifehacker.com/rss", Category: "Tech"})
+
+	var buffer bytes.Buffer
+	err = writeOPML(&buffer, config)
+	assert.Nil(t, err)
+
+	output := buffer.String()
+	assert.True(t, strings.HasPrefix(output, "<?xml"))
+	assert.Contains(t, output, `<opml version="2.0">`)
+	assert.Contains(t, output, `<title>`+opmlTitle+`</title>`)
+	assert.Equal(t, 1, strings.Count(output, `text="Tech"`))
+
+	roundTrip, err := parseOPML(&buffer)
+	assert.Nil(t, err)
+	assert.Equal(t, []Feed{
+		{URL: "https://www.theregister.com/offbeat/bofh/headlines.atom", Name: "Bastard Operator From Hell"},
+		{URL: "https://boingboing.net/feed/atom", Name: "Boing Boing", Category: "Tech"},
+		{URL: "https://barryodriscoll.net/feed/atom/", Name: "Barry", Category: "Tech/Blogs"},
+		{URL: "https://lifehacker.com/rss", Name: "https://lifehacker.com/rss", Category: "Tech"},
+	}, roundTrip.Feeds)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// starterFeeds feeds in the config created for someone running clacks for the first time
+var starterFeeds = []Feed{
+	{URL: "https://www.theregister.com/offbeat/bofh/headlines.atom"},
+	{URL: "https://barryodriscoll.net/feed/atom/"},
+	{URL: "https://boingboing.net/feed/atom"},
+	{URL: "https://lifehacker.com/rss"},
+}
+
+// Options settings given on the command line before the command, empty values are left at their defaults
+type Options struct {
+	ConfigFile string
+	DataDir    string
+	UserAgent  string
+	Timeout    time.Duration
+}
+
+// parseOptions parse the flags that come before the command, returns the options and the command with its arguments
+func parseOptions(args []string) (Options, []string, error) {
+	var options Options
+	flags := flag.NewFlagSet("clacks", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	flags.StringVar(&options.ConfigFile, "config", "", "config file")
+	flags.StringVar(&options.DataDir, "data-dir", "", "directory the entry store is kept in")
+	flags.StringVar(&options.UserAgent, "user-agent", userAgent, "user agent sent with requests")
+	flags.DurationVar(&options.Timeout, "timeout", defaultTimeout, "how long a request can take")
+
+	err := flags.Parse(args)
+	if err == flag.ErrHelp {
+		return Options{}, nil, errors.New(usage)
+	}
+	if err != nil {
+		return Options{}, nil, errors.New("error " + err.Error() + "\n\n" + usage)
+	}
+	if options.Timeout <= 0 {
+		return Options{}, nil, errors.New("error --timeout must be longer than 0s")
+	}
+	return options, flags.Args(), nil
+}
+
+// configFileLocations places the config file is looked for in order, the XDG config directory and then
+// the working directory
+func configFileLocations() []string {
+	var locations []string
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		locations = append(locations, filepath.Join(configHome, "clacks", configFileName))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		locations = append(locations, filepath.Join(home, ".config", "clacks", configFileName))
+	}
+	return append(locations, configFileName)
+}
+
+// findConfigFile first config file that exists, when there isn't one the first place looked so a config
+// can be created there
+func findConfigFile() string {
+	locations := configFileLocations()
+	for _, location := range locations {
+		if _, err := os.Stat(location); err == nil {
+			return location
+		}
+	}
+	return locations[0]
+}
+
+// offer to create a starter config when there is no config file, answers are read from in
+func (controller *Controller) offerStarterConfig(in io.Reader, out io.Writer) error {
+	if _, err := os.Stat(controller.configFileName); err == nil {
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(out, "No %s found, looked in %s\nCreate a starter config at %s? [Y/n] ",
+		configFileName, strings.Join(configFileLocations(), ", "), controller.configFileName)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if (err != nil && answer == "") || (answer != "" && answer != "y" && answer != "yes") {
+		_, _ = fmt.Fprintln(out)
+		return errors.New("error: could not find feeds.json config file")
+	}
+
+	err = writeConfigFile(controller.configFileName, &ConfigData{Feeds: starterFeeds})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "Created %s, add your own feeds to it\n", controller.configFileName)
+	return err
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseOptions(t *testing.T) {
+	options, command, err := parseOptions([]string{"--config", "my.json", "--data-dir=/tmp/clacks",
+		"--user-agent", "agent", "--timeout", "10s", "entries", "all", "--unread"})
+	assert.Nil(t, err)
+	assert.Equal(t, Options{ConfigFile: "my.json", DataDir: "/tmp/clacks", UserAgent: "agent", Timeout: 10 * time.Second}, options)
+	assert.Equal(t, []string{"entries", "all", "--unread"}, command)
+
+	options, command, err = parseOptions(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, Options{UserAgent: userAgent, Timeout: defaultTimeout}, options)
+	assert.Empty(t, command)
+}
+
+func TestParseOptionsErrors(t *testing.T) {
+	_, _, err := parseOptions([]string{"--bogus"})
+	assert.Equal(t, "error flag provided but not defined: -bogus\n\n"+usage, err.Error())
+
+	_, _, err = parseOptions([]string{"--help"})
+	assert.Equal(t, usage, err.Error())
+
+	_, _, err = parseOptions([]string{"--timeout", "0s"})
+	assert.Equal(t, "error --timeout must be longer than 0s", err.Error())
+}
+
+func TestFindConfigFileFollowsXDGConfigHome(t *testing.T) {
+	defer restoreEnv("XDG_CONFIG_HOME")()
+	defer restoreEnv("HOME")()
+	configHome := t.TempDir()
+	home := t.TempDir()
+	_ = os.Setenv("XDG_CONFIG_HOME", configHome)
+	_ = os.Setenv("HOME", home)
+
+	xdgConfig := filepath.Join(configHome, "clacks", configFileName)
+	homeConfig := filepath.Join(home, ".config", "clacks", configFileName)
+	assert.Equal(t, []string{xdgConfig, homeConfig, configFileName}, configFileLocations())
+
+	// the feeds.json of the working directory is found when there is no other config
+	assert.Equal(t, configFileName, findConfigFile())
+
+	assert.Nil(t, os.MkdirAll(filepath.Dir(homeConfig), 0700))
+	assert.Nil(t, ioutil.WriteFile(homeConfig, []byte(`{"feeds": []}`), 0600))
+	assert.Equal(t, homeConfig, findConfigFile())
+
+	assert.Nil(t, os.MkdirAll(filepath.Dir(xdgConfig), 0700))
+	assert.Nil(t, ioutil.WriteFile(xdgConfig, []byte(`{"feeds": []}`), 0600))
+	assert.Equal(t, xdgConfig, findConfigFile())
+
+	_ = os.Unsetenv("XDG_CONFIG_HOME")
+	assert.Equal(t, []string{homeConfig, configFileName}, configFileLocations())
+}
+
+func TestOfferStarterConfigCreatesConfig(t *testing.T) {
+	controller := Controller{configFileName: filepath.Join(t.TempDir(), "clacks", configFileName)}
+
+	var out bytes.Buffer
+	err := controller.offerStarterConfig(strings.NewReader("\n"), &out)
+	assert.Nil(t, err)
+	assert.Contains(t, out.String(), "Create a starter config at "+controller.configFileName+"? [Y/n] ")
+	assert.Contains(t, out.String(), "Created "+controller.configFileName)
+
+	data := NewData(nil)
+	assert.Nil(t, data.loadJSONConfig(controller.configFileName))
+	assert.Equal(t, starterFeeds, data.configData.Feeds)
+
+	// nothing is asked once there is a config
+	out.Reset()
+	assert.Nil(t, controller.offerStarterConfig(strings.NewReader(""), &out))
+	assert.Empty(t, out.String())
+}
+
+func TestOfferStarterConfigDeclined(t *testing.T) {
+	controller := Controller{configFileName: filepath.Join(t.TempDir(), configFileName)}
+
+	for _, answer := range []string{"n\n", "no\n", ""} {
+		err := controller.offerStarterConfig(strings.NewReader(answer), &bytes.Buffer{})
+		assert.Equal(t, "error: could not find feeds.json config file", err.Error())
+		_, statErr := os.Stat(controller.configFileName)
+		assert.True(t, os.IsNotExist(statErr))
+	}
+}
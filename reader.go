@@ -0,0 +1,328 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// maxArticleSize most bytes of a web page read when fetching an article
+const maxArticleSize = 5 << 20
+
+// minParagraphLength paragraphs shorter than this don't count towards the score of their container
+const minParagraphLength = 25
+
+// patterns of class and id attributes of elements that are likely and unlikely to hold an article
+var likelyArticlePattern = regexp.MustCompile(`(?i)article|body|content|entry|main|page|post|story|text`)
+var unlikelyArticlePattern = regexp.MustCompile(`(?i)ad-|advert|banner|breadcrumb|combx|comment|community|cookie|disqus|` +
+	`footer|header|menu|modal|nav|popup|promo|related|remark|share|shoutbox|sidebar|social|sponsor|subscribe|widget`)
+
+// HTTPArticleFetcher ArticleFetcher that downloads web pages over http
+type HTTPArticleFetcher struct {
+	client    *http.Client
+	UserAgent string
+}
+
+// NewHTTPArticleFetcher factory method for article fetcher
+func NewHTTPArticleFetcher() *HTTPArticleFetcher {
+	return &HTTPArticleFetcher{client: &http.Client{Timeout: defaultTimeout}, UserAgent: userAgent}
+}
+
+// FetchArticle download the web page at pageURL and extract its main content as html
+func (fetcher *HTTPArticleFetcher) FetchArticle(pageURL string, settings RequestSettings) (string, error) {
+	request, err := newRequest(pageURL, fetcher.UserAgent, settings)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := clientFor(fetcher.client, settings).Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return "", errors.New(response.Status)
+	}
+	return extractArticle(io.LimitReader(response.Body, maxArticleSize))
+}
+
+// extractArticle main content of a web page as html, the element holding the most paragraph text that isn't
+// links is taken to be the article after clutter such as navigation, sidebars and comments is removed
+func extractArticle(r io.Reader) (string, error) {
+	document, err := html.Parse(r)
+	if err != nil {
+		return "", err
+	}
+
+	removeClutter(document)
+	article := bestCandidate(document)
+	if article == nil {
+		return "", errors.New("no article found in page")
+	}
+
+	var buffer bytes.Buffer
+	for child := article.FirstChild; child != nil; child = child.NextSibling {
+		err = html.Render(&buffer, child)
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimSpace(buffer.String()), nil
+}
+
+// remove elements that never hold the article and elements whose class or id says they are clutter
+func removeClutter(document *html.Node) {
+	var clutter []*html.Node
+	var find func(node *html.Node)
+	find = func(node *html.Node) {
+		if node.Type == html.ElementNode {
+			switch node.DataAtom {
+			case atom.Script, atom.Style, atom.Noscript, atom.Nav, atom.Footer, atom.Aside, atom.Form,
+				atom.Iframe, atom.Svg, atom.Button, atom.Select, atom.Header:
+				clutter = append(clutter, node)
+				return
+			case atom.Html, atom.Body, atom.Article, atom.Main:
+			default:
+				if isUnlikelyCandidate(node) {
+					clutter = append(clutter, node)
+					return
+				}
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			find(child)
+		}
+	}
+	find(document)
+
+	for _, node := range clutter {
+		node.Parent.RemoveChild(node)
+	}
+}
+
+// whether the class and id of node say it is clutter and not part of the article
+func isUnlikelyCandidate(node *html.Node) bool {
+	names := attribute(node, "class") + " " + attribute(node, "id")
+	return unlikelyArticlePattern.MatchString(names) && !likelyArticlePattern.MatchString(names)
+}
+
+// bestCandidate element most likely to hold the article, scored by the paragraphs inside it
+func bestCandidate(document *html.Node) *html.Node {
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+	addScore := func(node *html.Node, score float64) {
+		if node == nil || node.Type != html.ElementNode {
+			return
+		}
+		if _, ok := scores[node]; !ok {
+			scores[node] = classWeight(node) + tagWeight(node)
+			candidates = append(candidates, node)
+		}
+		scores[node] += score
+	}
+
+	var score func(node *html.Node)
+	score = func(node *html.Node) {
+		if node.Type == html.ElementNode {
+			switch node.DataAtom {
+			case atom.P, atom.Pre, atom.Td, atom.Blockquote:
+				text := strings.TrimSpace(textContent(node))
+				if len(text) >= minParagraphLength {
+					// longer paragraphs with more clauses are more likely to be article text
+					paragraphScore := 1 + float64(strings.Count(text, ",")) + float64(len(text)/100)
+					if paragraphScore > 4 {
+						paragraphScore = 4
+					}
+					addScore(node.Parent, paragraphScore)
+					if node.Parent != nil {
+						addScore(node.Parent.Parent, paragraphScore/2)
+					}
+				}
+				return
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			score(child)
+		}
+	}
+	score(document)
+
+	var best *html.Node
+	bestScore := 0.0
+	for _, candidate := range candidates {
+		candidateScore := scores[candidate] * (1 - linkDensity(candidate))
+		if best == nil || candidateScore > bestScore {
+			best, bestScore = candidate, candidateScore
+		}
+	}
+	return best
+}
+
+// score of a candidate from its class and id
+func classWeight(node *html.Node) float64 {
+	names := attribute(node, "class") + " " + attribute(node, "id")
+	weight := 0.0
+	if likelyArticlePattern.MatchString(names) {
+		weight += 25
+	}
+	if unlikelyArticlePattern.MatchString(names) {
+		weight -= 25
+	}
+	return weight
+}
+
+// score of a candidate from its tag
+func tagWeight(node *html.Node) float64 {
+	switch node.DataAtom {
+	case atom.Article, atom.Main:
+		return 10
+	case atom.Div, atom.Section:
+		return 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		return 3
+	case atom.Ol, atom.Ul, atom.Dl, atom.Li:
+		return -3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		return -5
+	}
+	return 0
+}
+
+// linkDensity share of the text of node that is inside links
+func linkDensity(node *html.Node) float64 {
+	text := len(textContent(node))
+	if text == 0 {
+		return 0
+	}
+	links := 0
+	var count func(node *html.Node)
+	count = func(node *html.Node) {
+		if node.Type == html.ElementNode && node.DataAtom == atom.A {
+			links += len(textContent(node))
+			return
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			count(child)
+		}
+	}
+	count(node)
+	return float64(links) / float64(text)
+}
+
+// textContent text of node and every node inside it
+func textContent(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+	var text strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		text.WriteString(textContent(child))
+	}
+	return text.String()
+}
+
+// fetch the full article of an entry from its web page and keep it with the entry in memory and in the store
+func (data *Data) fetchFullContent(entry Entry) error {
+	if entry.url == "" {
+		return errors.New("error entry has no link to fetch the article from")
+	}
+	feed, _ := data.config().feed(entry.feedURL)
+	article, err := data.articles.FetchArticle(entry.url, feed.requestSettings())
+	if err != nil {
+		return errors.New("error fetching article: " + err.Error())
+	}
+
+	data.safeFeedData.SetFullContent(entry.feedURL, entry.guid, article)
+	if data.store != nil {
+		return data.store.SetFullContent(entry.feedURL, entry.guid, article)
+	}
+	return nil
+}
+
+// keep full articles already fetched for entries of a feed that has been fetched again
+func keepFullContent(entries []Entry, previous []Entry) {
+	fullContent := make(map[string]string)
+	for _, entry := range previous {
+		if entry.fullContent != "" {
+			fullContent[entry.guid] = entry.fullContent
+		}
+	}
+	for i := range entries {
+		if entries[i].fullContent == "" {
+			entries[i].fullContent = fullContent[entries[i].guid]
+		}
+	}
+}
+
+// readerMode whether the full articles of the entries of the feed at url are fetched as they are shown,
+// either set with reader_mode or a content setting of full-text
+func (config *ConfigData) readerMode(url string) bool {
+	feed, _ := config.feed(url)
+	return feed.ReaderMode || feed.Content == fullTextContent
+}
+
+// selected entry of the entries list
+func (ui *UI) selectedEntry() (Entry, bool) {
+	entries := ui.listedEntries()
+	i := ui.entriesList.GetCurrentItem()
+	if i < 0 || i >= len(entries) {
+		return Entry{}, false
+	}
+	return entries[i], true
+}
+
+// fetch the full article of the selected entry when the user asks for it
+func (ui *UI) readSelectedEntry() {
+	entry, ok := ui.selectedEntry()
+	if !ok || !ui.showingEntry() {
+		return
+	}
+	ui.startFetchingArticle(entry, true)
+}
+
+// fetch the full article of an entry of a feed in reader mode the first time it is shown
+func (ui *UI) autoFetchArticle(entry Entry) {
+	ref := entryRef{entry.feedURL, entry.guid}
+	if entry.fullContent != "" || ui.autoFetchedArticles[ref] || !ui.data.configData.readerMode(entry.feedURL) {
+		return
+	}
+	ui.autoFetchedArticles[ref] = true
+	ui.startFetchingArticle(entry, false)
+}
+
+// fetch the full article of entry in the background unless it is already being fetched,
+// errors are only shown when the user asked for the article
+func (ui *UI) startFetchingArticle(entry Entry, showErrors bool) {
+	ref := entryRef{entry.feedURL, entry.guid}
+	if ui.fetchingArticles[ref] {
+		return
+	}
+	ui.fetchingArticles[ref] = true
+	ui.entryTextView.SetTitle("Description - fetching article")
+	go ui.fetchArticle(entry, showErrors)
+}
+
+// fetch the full article of entry and show it if the entry is still selected
+func (ui *UI) fetchArticle(entry Entry, showErrors bool) {
+	err := ui.data.fetchFullContent(entry)
+	ui.app.QueueUpdateDraw(func() {
+		delete(ui.fetchingArticles, entryRef{entry.feedURL, entry.guid})
+		ui.entryTextView.SetTitle("Description")
+		if err != nil {
+			if showErrors {
+				ui.createMessagePage(err.Error())
+			}
+			return
+		}
+		ui.refreshListedEntries()
+		if selected, ok := ui.selectedEntry(); ok && selected.feedURL == entry.feedURL && selected.guid == entry.guid {
+			ui.loadEntryTextView(ui.entriesList.GetCurrentItem())
+		}
+	})
+}
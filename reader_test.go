@@ -0,0 +1,153 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testArticlePage = `<html><head><title>Post</title><script>track()</script></head><body>
+<nav><a href="/">Home</a> <a href="/about">About</a></nav>
+<div class="sidebar"><p>Subscribe to our newsletter, it is great, really, honestly.</p></div>
+<div id="main" class="post-content">
+  <h2>The real article</h2>
+  <p>This is the first paragraph of the article, with enough text in it to count.</p>
+  <p>This is the second paragraph, which also has plenty of words, commas, and text.</p>
+  <div class="share-links"><a href="/share">Share this</a></div>
+</div>
+<div class="comments"><p>First comment, which is long enough to look like a paragraph.</p></div>
+<footer><p>Copyright someone, all rights reserved, for ever and ever.</p></footer>
+</body></html>`
+
+func TestExtractArticleFindsMainContent(t *testing.T) {
+	article, err := extractArticle(strings.NewReader(testArticlePage))
+	assert.Nil(t, err)
+
+	assert.Contains(t, article, "<h2>The real article</h2>")
+	assert.Contains(t, article, "first paragraph of the article")
+	assert.Contains(t, article, "second paragraph")
+	assert.NotContains(t, article, "Home")
+	assert.NotContains(t, article, "newsletter")
+	assert.NotContains(t, article, "Share this")
+	assert.NotContains(t, article, "First comment")
+	assert.NotContains(t, article, "Copyright")
+}
+
+func TestExtractArticleWithoutParagraphs(t *testing.T) {
+	_, err := extractArticle(strings.NewReader("<html><body><a href='/'>Home</a></body></html>"))
+	assert.NotNil(t, err)
+	assert.Equal(t, "no article found in page", err.Error())
+}
+
+func TestHTTPArticleFetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/post" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, userAgent, r.Header.Get("User-Agent"))
+		_, _ = w.Write([]byte(testArticlePage))
+	}))
+	defer server.Close()
+
+	fetcher := NewHTTPArticleFetcher()
+	article, err := fetcher.FetchArticle(server.URL+"/post", RequestSettings{})
+	assert.Nil(t, err)
+	assert.Contains(t, article, "first paragraph of the article")
+
+	_, err = fetcher.FetchArticle(server.URL+"/missing", RequestSettings{})
+	assert.NotNil(t, err)
+	assert.Equal(t, "404 Not Found", err.Error())
+}
+
+func TestFetchFullContentKeepsArticleWithEntry(t *testing.T) {
+	data := createTestData(false)
+	store, err := OpenBoltStore(filepath.Join(t.TempDir(), storeFileName))
+	assert.Nil(t, err)
+	defer store.Close()
+	data.store = store
+	_, err = store.MergeFeed(testURLOne, data.safeFeedData.GetEntries(testURLOne))
+	assert.Nil(t, err)
+	data.articles = StubbedArticleFetcher{article: "<p>the whole article about hedgehogs</p>"}
+
+	entry := data.feedEntries(testURLOne)[0]
+	assert.Nil(t, data.fetchFullContent(entry))
+
+	assert.Equal(t, "<p>the whole article about hedgehogs</p>", data.feedEntries(testURLOne)[0].fullContent)
+	assert.Len(t, data.searchEntries("hedgehogs"), 1)
+
+	// the article is saved and kept when the feed is fetched again
+	merged, err := store.MergeFeed(testURLOne, createFakeFeedDataModel("registry", testURLOne))
+	assert.Nil(t, err)
+	assert.Equal(t, "<p>the whole article about hedgehogs</p>", merged.entries[0].fullContent)
+}
+
+func TestFetchFullContentWithError(t *testing.T) {
+	data := createTestData(false)
+	data.articles = StubbedArticleFetcher{withError: true}
+
+	err := data.fetchFullContent(data.feedEntries(testURLOne)[0])
+	assert.NotNil(t, err)
+	assert.Equal(t, "error fetching article: stubbed article fetcher error", err.Error())
+
+	err = data.fetchFullContent(Entry{guid: "no link"})
+	assert.Equal(t, "error entry has no link to fetch the article from", err.Error())
+}
+
+func TestKeepFullContent(t *testing.T) {
+	entries := []Entry{{guid: "1"}, {guid: "2"}, {guid: "3", fullContent: "newer"}}
+	keepFullContent(entries, []Entry{{guid: "1", fullContent: "article"}, {guid: "3", fullContent: "older"}})
+
+	assert.Equal(t, []Entry{{guid: "1", fullContent: "article"}, {guid: "2"}, {guid: "3", fullContent: "newer"}}, entries)
+}
+
+func TestReaderModeFeedFetchesArticleWhenEntryIsShown(t *testing.T) {
+	data := createTestData(false)
+	data.configData.Feeds[0].ReaderMode = true
+	data.articles = StubbedArticleFetcher{article: "<p>the whole article</p>"}
+	app := CreateStubbedApp(false).(*StubbedApp)
+	ui := CreateUI(app, data)
+
+	assert.True(t, data.configData.readerMode(testURLOne))
+	assert.False(t, data.configData.readerMode(testURLTwo))
+
+	ui.updateInterface()
+	for _, f := range app.QueuedUpdateDraws() {
+		f()
+	}
+	assert.Contains(t, ui.entryTextView.GetText(true), "registry fake content one")
+	assert.Equal(t, "Description - fetching article", ui.entryTextView.GetTitle())
+
+	// the article is shown once it has been fetched
+	queued := len(app.QueuedUpdateDraws())
+	assert.Eventually(t, func() bool { return len(app.QueuedUpdateDraws()) > queued }, time.Second, 10*time.Millisecond)
+	app.QueuedUpdateDraws()[queued]()
+	assert.Contains(t, ui.entryTextView.GetText(true), "the whole article")
+	assert.Equal(t, "Description", ui.entryTextView.GetTitle())
+
+	// entries of feeds without reader mode are shown as they are
+	ui.feedList.SetCurrentItem(2)
+	ui.loadEntryTextView(0)
+	assert.Equal(t, "Description", ui.entryTextView.GetTitle())
+}
+
+func TestReaderModeActionShowsErrors(t *testing.T) {
+	data := createTestData(false)
+	data.articles = StubbedArticleFetcher{withError: true}
+	app := CreateStubbedApp(false).(*StubbedApp)
+	ui := CreateUI(app, data)
+	ui.setupLists()
+
+	entry, ok := ui.selectedEntry()
+	assert.True(t, ok)
+	ui.fetchArticle(entry, true)
+	draws := app.QueuedUpdateDraws()
+	draws[len(draws)-1]()
+
+	frontPage, _ := ui.pages.GetFrontPage()
+	assert.Equal(t, messagePage, frontPage)
+}
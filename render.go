@@ -0,0 +1,301 @@
+package main
+
+import (
+	"fmt"
+	strip "github.com/grokify/html-strip-tags-go"
+	"github.com/rivo/tview"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"net/url"
+	"strings"
+)
+
+// quoteIndent indent of each level of quote and code block
+const quoteIndent = "    "
+
+// horizontalRule text drawn for an hr element
+const horizontalRule = "────────────────────"
+
+// renderContent content of an entry as text with tview color tags, html is formatted and content without any
+// tags is shown as it is, also returns the links of the content in the order they are numbered resolved
+// against the entry's url
+func renderContent(content, entryURL string) (string, []string) {
+	if !strings.Contains(content, "<") {
+		return tview.Escape(html.UnescapeString(content)), nil
+	}
+
+	nodes, err := html.ParseFragment(strings.NewReader(content), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return tview.Escape(plainText(content)), nil
+	}
+
+	renderer := &htmlRenderer{}
+	renderer.base, _ = url.Parse(entryURL)
+	for _, node := range nodes {
+		renderer.render(node)
+	}
+	return renderer.text(), renderer.links
+}
+
+// plainText text of html content without its tags
+func plainText(content string) string {
+	return html.UnescapeString(strip.StripTags(content))
+}
+
+// listState kind of list being rendered and how many items it has had so far
+type listState struct {
+	ordered bool
+	items   int
+}
+
+// htmlRenderer state of an html document being turned into text
+type htmlRenderer struct {
+	out        strings.Builder
+	base       *url.URL
+	links      []string
+	lists      []listState
+	quotes     int
+	pre        int
+	newlines   int
+	lineStart  bool
+	hasContent bool
+	// space whether the text written so far ends in a space
+	space bool
+	// tags opened since text was last written, held back so they follow any line breaks
+	openTags string
+}
+
+// render node and its children
+func (renderer *htmlRenderer) render(node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		renderer.writeText(node.Data)
+		return
+	case html.ElementNode:
+	default:
+		renderer.renderChildren(node)
+		return
+	}
+
+	switch node.DataAtom {
+	case atom.Script, atom.Style, atom.Head, atom.Title:
+		return
+	case atom.Br:
+		renderer.breakLines(1)
+	case atom.Hr:
+		renderer.breakLines(1)
+		renderer.write(horizontalRule)
+		renderer.breakLines(1)
+	case atom.H1, atom.H2:
+		renderer.renderBlock(node, "[::bu]", "[::-]")
+	case atom.H3, atom.H4, atom.H5, atom.H6:
+		renderer.renderBlock(node, "[::b]", "[::-]")
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Figure, atom.Table, atom.Dl:
+		renderer.renderBlock(node, "", "")
+	case atom.Tr, atom.Dt, atom.Dd, atom.Figcaption:
+		renderer.breakLines(1)
+		renderer.renderChildren(node)
+		renderer.breakLines(1)
+	case atom.Blockquote:
+		renderer.breakLines(2)
+		renderer.quotes++
+		renderer.renderChildren(node)
+		renderer.quotes--
+		renderer.breakLines(2)
+	case atom.Pre:
+		renderer.breakLines(2)
+		renderer.quotes++
+		renderer.pre++
+		renderer.renderChildren(node)
+		renderer.pre--
+		renderer.quotes--
+		renderer.breakLines(2)
+	case atom.Ul, atom.Ol:
+		renderer.breakLines(1)
+		renderer.lists = append(renderer.lists, listState{ordered: node.DataAtom == atom.Ol})
+		renderer.renderChildren(node)
+		renderer.lists = renderer.lists[:len(renderer.lists)-1]
+		renderer.breakLines(1)
+	case atom.Li:
+		renderer.renderListItem(node)
+	case atom.Strong, atom.B:
+		renderer.renderInline(node, "[::b]", "[::-]")
+	case atom.Em, atom.I:
+		renderer.renderInline(node, "[::i]", "[::-]")
+	case atom.A:
+		renderer.renderChildren(node)
+		renderer.addLink(attribute(node, "href"))
+	case atom.Img:
+		if alt := strings.TrimSpace(attribute(node, "alt")); alt != "" {
+			renderer.writeText("[image: " + alt + "]")
+		}
+	default:
+		renderer.renderChildren(node)
+	}
+}
+
+// render the children of node in order
+func (renderer *htmlRenderer) renderChildren(node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		renderer.render(child)
+	}
+}
+
+// render a block element on lines of its own between start and end tags
+func (renderer *htmlRenderer) renderBlock(node *html.Node, start, end string) {
+	renderer.breakLines(2)
+	renderer.openTag(start)
+	renderer.renderChildren(node)
+	renderer.closeTag(end)
+	renderer.breakLines(2)
+}
+
+// render an inline element between start and end tags
+func (renderer *htmlRenderer) renderInline(node *html.Node, start, end string) {
+	renderer.openTag(start)
+	renderer.renderChildren(node)
+	renderer.closeTag(end)
+}
+
+// render a list item on a new line after its bullet or number
+func (renderer *htmlRenderer) renderListItem(node *html.Node) {
+	renderer.breakLines(1)
+	marker := "• "
+	if len(renderer.lists) > 0 {
+		list := &renderer.lists[len(renderer.lists)-1]
+		list.items++
+		if list.ordered {
+			marker = fmt.Sprintf("%d. ", list.items)
+		}
+	}
+	renderer.write(marker)
+	renderer.renderChildren(node)
+	renderer.breakLines(1)
+}
+
+// number a link and show its number after the link text, the links are listed at the end
+func (renderer *htmlRenderer) addLink(href string) {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+		return
+	}
+	if renderer.base != nil {
+		if resolved, err := renderer.base.Parse(href); err == nil {
+			href = resolved.String()
+		}
+	}
+	renderer.links = append(renderer.links, href)
+	renderer.write(tview.Escape(fmt.Sprintf("[%d]", len(renderer.links))))
+}
+
+// ask for at least count line breaks before the next text
+func (renderer *htmlRenderer) breakLines(count int) {
+	if renderer.hasContent && count > renderer.newlines {
+		renderer.newlines = count
+	}
+}
+
+// write text from the document, whitespace is collapsed outside of pre blocks
+func (renderer *htmlRenderer) writeText(text string) {
+	if renderer.pre > 0 {
+		lines := strings.Split(text, "\n")
+		for i, line := range lines {
+			if i > 0 {
+				renderer.newlines = 1
+				renderer.hasContent = true
+			}
+			if line != "" {
+				renderer.write(tview.Escape(line))
+			}
+		}
+		return
+	}
+
+	collapsed := strings.Join(strings.Fields(text), " ")
+	if collapsed == "" {
+		if text != "" && !renderer.lineStart && renderer.newlines == 0 && renderer.hasContent && !renderer.space {
+			renderer.out.WriteString(" ")
+			renderer.space = true
+		}
+		return
+	}
+	if (text[0] == ' ' || text[0] == '\n' || text[0] == '\t') && !renderer.space {
+		collapsed = " " + collapsed
+	}
+	if last := text[len(text)-1]; last == ' ' || last == '\n' || last == '\t' {
+		collapsed += " "
+	}
+	renderer.write(tview.Escape(collapsed))
+}
+
+// open a color tag before the next text written
+func (renderer *htmlRenderer) openTag(tag string) {
+	renderer.openTags += tag
+}
+
+// close a color tag after the text written so far, tags opened around no text are dropped
+func (renderer *htmlRenderer) closeTag(tag string) {
+	if renderer.openTags != "" {
+		renderer.openTags = ""
+		return
+	}
+	renderer.out.WriteString(tag)
+}
+
+// write text that has already been escaped, starting any line breaks asked for first
+func (renderer *htmlRenderer) write(text string) {
+	if renderer.newlines > 0 {
+		renderer.out.WriteString(strings.Repeat("\n", renderer.newlines))
+		renderer.newlines = 0
+		renderer.lineStart = true
+	}
+	if renderer.lineStart || !renderer.hasContent {
+		if renderer.pre == 0 {
+			text = strings.TrimLeft(text, " ")
+		}
+		if text == "" {
+			return
+		}
+		renderer.out.WriteString(renderer.indent())
+	}
+	renderer.out.WriteString(renderer.openTags + text)
+	renderer.openTags = ""
+	renderer.lineStart = false
+	renderer.hasContent = true
+	renderer.space = strings.HasSuffix(text, " ")
+}
+
+// indent of lines inside quotes and lists
+func (renderer *htmlRenderer) indent() string {
+	indent := strings.Repeat(quoteIndent, renderer.quotes)
+	if len(renderer.lists) > 1 {
+		indent += strings.Repeat("  ", len(renderer.lists)-1)
+	}
+	return indent
+}
+
+// rendered text followed by the numbered list of links
+func (renderer *htmlRenderer) text() string {
+	text := strings.TrimRight(renderer.out.String(), " \n")
+	if len(renderer.links) == 0 {
+		return text
+	}
+
+	var footnotes strings.Builder
+	footnotes.WriteString(text)
+	footnotes.WriteString("\n\n[::b]Links:[::-]\n")
+	for i, link := range renderer.links {
+		footnotes.WriteString(tview.Escape(fmt.Sprintf("[%d] %s", i+1, link)) + "\n")
+	}
+	return strings.TrimRight(footnotes.String(), "\n")
+}
+
+// value of an attribute of node, empty when node doesn't have it
+func attribute(node *html.Node, name string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == name {
+			return attr.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRenderContentWithoutTagsIsEscaped(t *testing.T) {
+	rendered, links := renderContent("fish &amp; chips [red]", "")
+
+	assert.Equal(t, "fish & chips [red[]", rendered)
+	assert.Empty(t, links)
+}
+
+func TestRenderContentHeadingsAndParagraphs(t *testing.T) {
+	rendered, _ := renderContent("<h1>Title</h1><p>First\n  paragraph with <b>bold</b> and <em>emphasis</em>.</p><h3>Part</h3><p>Second<br>line</p>", "")
+
+	assert.Equal(t, "[::bu]Title[::-]\n\nFirst paragraph with [::b]bold[::-] and [::i]emphasis[::-].\n\n"+
+		"[::b]Part[::-]\n\nSecond\nline", rendered)
+}
+
+func TestRenderContentLists(t *testing.T) {
+	rendered, _ := renderContent("<ul><li>one</li><li>two<ol><li>first</li><li>second</li></ol></li></ul><p>after</p>", "")
+
+	assert.Equal(t, "• one\n• two\n  1. first\n  2. second\n\nafter", rendered)
+}
+
+func TestRenderContentQuotesAndCode(t *testing.T) {
+	rendered, _ := renderContent("<blockquote><p>quoted</p></blockquote><pre>if a {\n  b[0]\n}</pre>", "")
+
+	assert.Equal(t, "    quoted\n\n    if a {\n      b[0[]\n    }", rendered)
+}
+
+func TestRenderContentNumbersLinks(t *testing.T) {
+	rendered, links := renderContent(`<p>See <a href="/about">about</a>, <a href="https://other.org/">other</a>`+
+		`, <a href="#top">top</a> and <a href="https://other.org/">again</a>.</p><script>alert(1)</script>`,
+		"https://example.com/posts/1")
+
+	assert.Equal(t, "See about[1[], other[2[], top and again[3[].\n\n[::b]Links:[::-]\n"+
+		"[1[] https://example.com/about\n[2[] https://other.org/\n[3[] https://other.org/", rendered)
+	assert.Equal(t, []string{"https://example.com/about", "https://other.org/", "https://other.org/"}, links)
+}
+
+func TestRenderContentImages(t *testing.T) {
+	rendered, _ := renderContent(`a <img src="cat.png" alt="cat"> <img src="x.png"> b`, "")
+
+	assert.Equal(t, "a [image: cat[] b", rendered)
+}
+
+func TestPlainText(t *testing.T) {
+	assert.Equal(t, "fish & chips", plainText("<p>fish &amp; <i>chips</i></p>"))
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"github.com/mmcdole/gofeed"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultRefreshInterval how often feeds are refreshed when the config doesn't set an interval
+const defaultRefreshInterval = 30 * time.Minute
+
+// schedulerTick how often the scheduler checks for feeds that are due a refresh
+const schedulerTick = time.Minute
+
+// ttlKey key in gofeed.Feed.Custom the rss ttl is copied to, see ttlRSSTranslator
+const ttlKey = "ttl"
+
+// Scheduler refreshes feeds in the background once their refresh interval has passed
+type Scheduler struct {
+	data          *Data
+	feedRefreshed func(url string)
+	now           func() time.Time
+	stop          chan struct{}
+	stopOnce      sync.Once
+}
+
+// NewScheduler factory method for scheduler, feedRefreshed is called with the url of each refreshed feed
+func NewScheduler(data *Data, feedRefreshed func(url string)) *Scheduler {
+	return &Scheduler{
+		data:          data,
+		feedRefreshed: feedRefreshed,
+		now:           time.Now,
+		stop:          make(chan struct{}),
+	}
+}
+
+// Start check for due feeds every tick until Stop is called
+func (scheduler *Scheduler) Start(tick time.Duration) {
+	go func() {
+		ticker := time.NewTicker(tick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				scheduler.refreshDueFeeds()
+			case <-scheduler.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stop checking for due feeds
+func (scheduler *Scheduler) Stop() {
+	scheduler.stopOnce.Do(func() {
+		close(scheduler.stop)
+	})
+}
+
+// refresh every feed whose interval has passed since it was last fetched
+func (scheduler *Scheduler) refreshDueFeeds() {
+	due := scheduler.dueFeeds()
+	if len(due) > 0 {
+		scheduler.data.fetchFeeds(due, scheduler.feedRefreshed)
+	}
+}
+
+// urls of feeds due a refresh, feeds that haven't been fetched yet are left to the initial load
+// and disabled feeds are never refreshed
+func (scheduler *Scheduler) dueFeeds() []string {
+	var due []string
+	for _, feed := range scheduler.data.config().enabledFeeds() {
+		fetched := scheduler.data.safeFeedData.GetEntries(feed.URL).fetched
+		if fetched.IsZero() {
+			continue
+		}
+		if scheduler.now().Sub(fetched) >= scheduler.data.refreshInterval(feed) {
+			due = append(due, feed.URL)
+		}
+	}
+	return due
+}
+
+// refreshInterval how often a feed is refreshed, an interval set on the feed is used as is
+// otherwise the global interval is used unless the publisher asks to be polled less often
+func (data *Data) refreshInterval(feed Feed) time.Duration {
+	if feed.RefreshInterval > 0 {
+		return time.Duration(feed.RefreshInterval)
+	}
+
+	interval := defaultRefreshInterval
+	if config := data.config(); config.RefreshInterval > 0 {
+		interval = time.Duration(config.RefreshInterval)
+	}
+
+	if hint := data.safeFeedData.GetEntries(feed.URL).refreshHint; hint > interval {
+		interval = hint
+	}
+	return interval
+}
+
+// feedRefreshHint how often the publisher of a feed asks to be polled using rss ttl or the
+// syndication module's sy:updatePeriod and sy:updateFrequency, 0 when the feed doesn't say
+func feedRefreshHint(feed *gofeed.Feed) time.Duration {
+	if ttl, err := strconv.Atoi(feed.Custom[ttlKey]); err == nil && ttl > 0 {
+		return time.Duration(ttl) * time.Minute
+	}
+
+	syndication := feed.Extensions["sy"]
+	if len(syndication["updatePeriod"]) == 0 {
+		return 0
+	}
+
+	var period time.Duration
+	switch strings.TrimSpace(syndication["updatePeriod"][0].Value) {
+	case "hourly":
+		period = time.Hour
+	case "daily":
+		period = 24 * time.Hour
+	case "weekly":
+		period = 7 * 24 * time.Hour
+	case "monthly":
+		period = 30 * 24 * time.Hour
+	case "yearly":
+		period = 365 * 24 * time.Hour
+	default:
+		return 0
+	}
+
+	frequency := 1
+	if len(syndication["updateFrequency"]) > 0 {
+		parsed, err := strconv.Atoi(strings.TrimSpace(syndication["updateFrequency"][0].Value))
+		if err == nil && parsed > 0 {
+			frequency = parsed
+		}
+	}
+	return period / time.Duration(frequency)
+}
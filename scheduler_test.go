@@ -0,0 +1,121 @@
+package main
+
+import (
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRefreshIntervalUsesFeedThenGlobalThenDefault(t *testing.T) {
+	data := createTestData(false)
+
+	assert.Equal(t, defaultRefreshInterval, data.refreshInterval(Feed{URL: testURLOne}))
+
+	data.configData.RefreshInterval = Duration(10 * time.Minute)
+	assert.Equal(t, 10*time.Minute, data.refreshInterval(Feed{URL: testURLOne}))
+
+	feed := Feed{URL: testURLOne, RefreshInterval: Duration(5 * time.Minute)}
+	assert.Equal(t, 5*time.Minute, data.refreshInterval(feed))
+}
+
+func TestRefreshIntervalHonoursFeedHint(t *testing.T) {
+	data := createTestData(false)
+	data.configData.RefreshInterval = Duration(10 * time.Minute)
+
+	feedData := data.safeFeedData.GetEntries(testURLOne)
+	feedData.refreshHint = time.Hour
+	data.safeFeedData.SetSiteData(testURLOne, feedData)
+
+	assert.Equal(t, time.Hour, data.refreshInterval(Feed{URL: testURLOne}))
+
+	feed := Feed{URL: testURLOne, RefreshInterval: Duration(5 * time.Minute)}
+	assert.Equal(t, 5*time.Minute, data.refreshInterval(feed))
+}
+
+func TestFeedRefreshHint(t *testing.T) {
+	assert.Equal(t, time.Duration(0), feedRefreshHint(&gofeed.Feed{}))
+	assert.Equal(t, 90*time.Minute, feedRefreshHint(&gofeed.Feed{Custom: map[string]string{ttlKey: "90"}}))
+	assert.Equal(t, time.Duration(0), feedRefreshHint(&gofeed.Feed{Custom: map[string]string{ttlKey: "soon"}}))
+
+	syndication := func(period, frequency string) *gofeed.Feed {
+		extensions := map[string][]ext.Extension{"updatePeriod": {{Value: period}}}
+		if frequency != "" {
+			extensions["updateFrequency"] = []ext.Extension{{Value: frequency}}
+		}
+		return &gofeed.Feed{Extensions: ext.Extensions{"sy": extensions}}
+	}
+	assert.Equal(t, time.Hour, feedRefreshHint(syndication("hourly", "")))
+	assert.Equal(t, 12*time.Hour, feedRefreshHint(syndication("daily", "2")))
+	assert.Equal(t, 7*24*time.Hour, feedRefreshHint(syndication(" weekly ", "0")))
+	assert.Equal(t, time.Duration(0), feedRefreshHint(syndication("fortnightly", "")))
+}
+
+func TestTTLRSSTranslatorKeepsTTL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Replace(testRSS, "<title>Test RSS Feed</title>", "<title>Test RSS Feed</title><ttl>120</ttl>", 1)))
+	}))
+	defer server.Close()
+
+	feed, err := NewHTTPFeedParser(nil).ParseURL(server.URL, RequestSettings{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "120", feed.Custom[ttlKey])
+	assert.Equal(t, 2*time.Hour, feedRefreshHint(feed))
+}
+
+func TestSchedulerRefreshesOnlyDueFeeds(t *testing.T) {
+	data := createTestData(false)
+	data.configData.Feeds = append(data.configData.Feeds, Feed{URL: "never.fetched.com"})
+	data.configData.RefreshInterval = Duration(time.Hour)
+
+	now := time.Now()
+	setFetched := func(url string, fetched time.Time) {
+		feedData := data.safeFeedData.GetEntries(url)
+		feedData.fetched = fetched
+		data.safeFeedData.SetSiteData(url, feedData)
+	}
+	setFetched(testURLOne, now.Add(-2*time.Hour))
+	setFetched(testURLTwo, now.Add(-10*time.Minute))
+
+	var mutex sync.Mutex
+	var refreshed []string
+	scheduler := NewScheduler(data, func(url string) {
+		mutex.Lock()
+		refreshed = append(refreshed, url)
+		mutex.Unlock()
+	})
+	scheduler.now = func() time.Time { return now }
+
+	scheduler.refreshDueFeeds()
+
+	assert.Equal(t, []string{testURLOne}, refreshed)
+	assert.Equal(t, "Test Feed Title From Parser", data.safeFeedData.GetEntries(testURLOne).name)
+	assert.True(t, data.safeFeedData.GetEntries(testURLOne).fetched.After(now.Add(-time.Minute)))
+	assert.Equal(t, "google", data.safeFeedData.GetEntries(testURLTwo).name)
+}
+
+func TestSchedulerStartAndStop(t *testing.T) {
+	data := createTestData(false)
+	data.safeFeedData.SetSiteData(testURLOne, FeedDataModel{fetched: time.Now().Add(-time.Hour)})
+	data.configData.Feeds[0].RefreshInterval = Duration(time.Minute)
+
+	refreshed := make(chan string, 10)
+	scheduler := NewScheduler(data, func(url string) { refreshed <- url })
+	scheduler.Start(time.Millisecond)
+
+	select {
+	case url := <-refreshed:
+		assert.Equal(t, testURLOne, url)
+	case <-time.After(time.Second):
+		assert.Fail(t, "scheduler didn't refresh due feed")
+	}
+
+	scheduler.Stop()
+	scheduler.Stop()
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// searchResultsURL stands in for a feed url while the entries list shows search results
+const searchResultsURL = "clacks://search"
+
+// entryRef identifies an entry by the feed it belongs to and its guid
+type entryRef struct {
+	feedURL string
+	guid    string
+}
+
+// SearchIndex inverted index of the words in the titles and content of entries, version changes every time
+// the indexed entries do
+type SearchIndex struct {
+	mu       sync.RWMutex
+	postings map[string]map[entryRef]struct{}
+	feedDocs map[string]map[entryRef][]string
+	version  int
+}
+
+// NewSearchIndex factory method for an empty search index
+func NewSearchIndex() *SearchIndex {
+	return &SearchIndex{
+		postings: make(map[string]map[entryRef]struct{}),
+		feedDocs: make(map[string]map[entryRef][]string),
+	}
+}
+
+// IndexFeed replace the indexed entries of a feed with entries
+func (index *SearchIndex) IndexFeed(url string, entries []Entry) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	index.version++
+	index.removeFeed(url)
+	docs := make(map[entryRef][]string, len(entries))
+	for _, entry := range entries {
+		ref := entryRef{feedURL: url, guid: entry.guid}
+		terms := uniqueTerms(entry.title + " " + plainText(entry.content) + " " + plainText(entry.fullContent))
+		for _, term := range terms {
+			refs, ok := index.postings[term]
+			if !ok {
+				refs = make(map[entryRef]struct{})
+				index.postings[term] = refs
+			}
+			refs[ref] = struct{}{}
+		}
+		docs[ref] = terms
+	}
+	index.feedDocs[url] = docs
+}
+
+// Clear remove every entry from the index
+func (index *SearchIndex) Clear() {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	index.version++
+	index.postings = make(map[string]map[entryRef]struct{})
+	index.feedDocs = make(map[string]map[entryRef][]string)
+}
+
+// Version changes whenever entries are indexed or removed, results of a search are current while it is the same
+func (index *SearchIndex) Version() int {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+	return index.version
+}
+
+// Search entries containing every word of query, an empty query matches nothing
+func (index *SearchIndex) Search(query string) []entryRef {
+	terms := uniqueTerms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	// start from the rarest term so the intersection only shrinks
+	smallest := index.postings[terms[0]]
+	for _, term := range terms[1:] {
+		if len(index.postings[term]) < len(smallest) {
+			smallest = index.postings[term]
+		}
+	}
+
+	var results []entryRef
+	for ref := range smallest {
+		matchesAll := true
+		for _, term := range terms {
+			if _, ok := index.postings[term][ref]; !ok {
+				matchesAll = false
+				break
+			}
+		}
+		if matchesAll {
+			results = append(results, ref)
+		}
+	}
+	return results
+}
+
+// remove the postings of every entry of a feed, callers must hold the lock
+func (index *SearchIndex) removeFeed(url string) {
+	for ref, terms := range index.feedDocs[url] {
+		for _, term := range terms {
+			delete(index.postings[term], ref)
+			if len(index.postings[term]) == 0 {
+				delete(index.postings, term)
+			}
+		}
+	}
+	delete(index.feedDocs, url)
+}
+
+// lower case words of text without duplicates, anything that isn't a letter or digit separates words
+func uniqueTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool, len(words))
+	var terms []string
+	for _, word := range words {
+		if !seen[word] {
+			seen[word] = true
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// searchEntries entries of every feed matching query, newest first
+func (data *Data) searchEntries(query string) []Entry {
+	refs := data.safeFeedData.index.Search(query)
+
+	byFeed := make(map[string]map[string]bool)
+	for _, ref := range refs {
+		if byFeed[ref.feedURL] == nil {
+			byFeed[ref.feedURL] = make(map[string]bool)
+		}
+		byFeed[ref.feedURL][ref.guid] = true
+	}
+
+	var entries []Entry
+	for _, feed := range data.configData.enabledFeeds() {
+		guids := byFeed[feed.URL]
+		if guids == nil {
+			continue
+		}
+		for _, entry := range data.feedEntries(feed.URL) {
+			if guids[entry.guid] {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return sortEntries(entries, newestFirst)
+}
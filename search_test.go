@@ -0,0 +1,68 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSearchIndexMatchesEveryWordOfQuery(t *testing.T) {
+	index := NewSearchIndex()
+	index.IndexFeed(testURLOne, []Entry{
+		{guid: "one", title: "The Bastard Operator", content: "from hell"},
+		{guid: "two", title: "Operator's manual", content: "Nothing to see"},
+	})
+
+	assert.ElementsMatch(t, []entryRef{{testURLOne, "one"}, {testURLOne, "two"}}, index.Search("OPERATOR"))
+	assert.Equal(t, []entryRef{{testURLOne, "one"}}, index.Search("operator hell"))
+	assert.Empty(t, index.Search("operator heaven"))
+	assert.Empty(t, index.Search("  "))
+}
+
+func TestSearchIndexIgnoresMarkup(t *testing.T) {
+	index := NewSearchIndex()
+	index.IndexFeed(testURLOne, []Entry{{guid: "one", title: "Markup", content: `<p class="intro">fish &amp; chips</p>`}})
+
+	assert.Equal(t, []entryRef{{testURLOne, "one"}}, index.Search("fish chips"))
+	assert.Empty(t, index.Search("intro"))
+	assert.Empty(t, index.Search("amp"))
+}
+
+func TestSearchIndexReplacesEntriesOfFeed(t *testing.T) {
+	index := NewSearchIndex()
+	index.IndexFeed(testURLOne, []Entry{{guid: "one", title: "old title"}})
+	index.IndexFeed(testURLTwo, []Entry{{guid: "two", title: "old news"}})
+
+	index.IndexFeed(testURLOne, []Entry{{guid: "three", title: "new title"}})
+
+	assert.Equal(t, []entryRef{{testURLTwo, "two"}}, index.Search("old"))
+	assert.Equal(t, []entryRef{{testURLOne, "three"}}, index.Search("title"))
+
+	index.Clear()
+	assert.Empty(t, index.Search("title"))
+}
+
+func TestSearchIndexVersionChangesWithEntries(t *testing.T) {
+	index := NewSearchIndex()
+	version := index.Version()
+
+	index.Search("title")
+	assert.Equal(t, version, index.Version())
+
+	index.IndexFeed(testURLOne, []Entry{{guid: "one", title: "title"}})
+	assert.NotEqual(t, version, index.Version())
+
+	version = index.Version()
+	index.Clear()
+	assert.NotEqual(t, version, index.Version())
+}
+
+func TestSearchEntriesFindsEntriesOfEveryFeed(t *testing.T) {
+	data := createTestData(false)
+
+	entries := data.searchEntries("fake title one")
+
+	assert.Len(t, entries, 2)
+	assert.Equal(t, testURLOne, entries[0].feedURL)
+	assert.Equal(t, "registry fake title one", entries[0].title)
+	assert.Equal(t, testURLTwo, entries[1].feedURL)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// values of a feed's content setting, which field of an entry is shown or whether its full article is fetched
+const (
+	descriptionContent = "description"
+	contentContent     = "content"
+	fullTextContent    = "full-text"
+)
+
+// RequestSettings settings of the requests made for a feed, empty values leave the fetcher's defaults alone
+type RequestSettings struct {
+	UserAgent string
+	Headers   map[string]string
+	Timeout   time.Duration
+}
+
+// feed in the config with url
+func (config *ConfigData) feed(url string) (Feed, bool) {
+	for _, feed := range config.Feeds {
+		if feed.URL == url {
+			return feed, true
+		}
+	}
+	return Feed{}, false
+}
+
+// enabledFeeds feeds in the config that haven't been disabled, the only feeds that are fetched and listed
+func (config *ConfigData) enabledFeeds() []Feed {
+	var feeds []Feed
+	for _, feed := range config.Feeds {
+		if !feed.Disabled {
+			feeds = append(feeds, feed)
+		}
+	}
+	return feeds
+}
+
+// requestSettings settings of the requests made for the feed and the articles of its entries
+func (feed Feed) requestSettings() RequestSettings {
+	return RequestSettings{UserAgent: feed.UserAgent, Headers: feed.Headers, Timeout: time.Duration(feed.Timeout)}
+}
+
+// check the settings of every feed are ones clacks understands
+func (config *ConfigData) checkFeedSettings() error {
+	for _, feed := range config.Feeds {
+		switch feed.Content {
+		case "", descriptionContent, contentContent, fullTextContent:
+		default:
+			return errors.New("error unknown content " + feed.Content + " for feed " + feed.URL +
+				", use " + strings.Join([]string{descriptionContent, contentContent, fullTextContent}, ", "))
+		}
+		if feed.MaxEntries < 0 {
+			return errors.New("error max_entries of feed " + feed.URL + " can't be negative")
+		}
+		if feed.Timeout < 0 {
+			return errors.New("error timeout of feed " + feed.URL + " can't be negative")
+		}
+	}
+	return nil
+}
+
+// capEntries the first max entries, the ones most recently seen, every entry when max is 0
+func capEntries(entries []Entry, max int) []Entry {
+	if max > 0 && len(entries) > max {
+		return entries[:max]
+	}
+	return entries
+}
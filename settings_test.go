@@ -0,0 +1,86 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfigChecksFeedSettings(t *testing.T) {
+	config, err := parseConfig(strings.NewReader(`{"feeds": [{"url": "a", "name": "A", "user_agent": "agent",
+		"headers": {"Authorization": "Bearer token"}, "timeout": "5s", "max_entries": 10, "disabled": true,
+		"content": "description"}]}`))
+	assert.Nil(t, err)
+	assert.Equal(t, Feed{URL: "a", Name: "A", UserAgent: "agent", Headers: map[string]string{"Authorization": "Bearer token"},
+		Timeout: Duration(5 * time.Second), MaxEntries: 10, Disabled: true, Content: descriptionContent}, config.Feeds[0])
+	assert.Equal(t, RequestSettings{UserAgent: "agent", Headers: map[string]string{"Authorization": "Bearer token"},
+		Timeout: 5 * time.Second}, config.Feeds[0].requestSettings())
+
+	_, err = parseConfig(strings.NewReader(`{"feeds": [{"url": "a", "content": "summary"}]}`))
+	assert.Equal(t, "error unknown content summary for feed a, use description, content, full-text", err.Error())
+
+	_, err = parseConfig(strings.NewReader(`{"feeds": [{"url": "a", "max_entries": -1}]}`))
+	assert.Equal(t, "error max_entries of feed a can't be negative", err.Error())
+}
+
+func TestDisabledFeedsAreNotFetchedOrListed(t *testing.T) {
+	fakeFeed := CreateTestFeed()
+	data := NewData(createStubbedParser(&fakeFeed, false))
+	data.configData = &ConfigData{Feeds: []Feed{{URL: testURLOne}, {URL: testURLTwo, Disabled: true}}}
+
+	assert.Nil(t, data.loadDataFromFeeds(nil))
+	assert.Len(t, data.safeFeedData.GetEntries(testURLOne).entries, 2)
+	assert.Empty(t, data.safeFeedData.GetEntries(testURLTwo).entries)
+	assert.Equal(t, []Feed{{URL: testURLOne}}, data.configData.enabledFeeds())
+
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+	assert.Equal(t, 2, ui.feedList.GetItemCount())
+	assert.Equal(t, -1, ui.feedRowIndex(testURLTwo))
+}
+
+func TestMaxEntriesCapsEntriesKeptInStore(t *testing.T) {
+	store, err := OpenBoltStore(filepath.Join(t.TempDir(), storeFileName))
+	assert.Nil(t, err)
+	defer store.Close()
+	_, err = store.MergeFeed(testURLOne, FeedDataModel{name: "old name", entries: []Entry{{guid: "old", title: "Old Entry"}}})
+	assert.Nil(t, err)
+
+	fakeFeed := CreateTestFeed()
+	data := NewData(createStubbedParser(&fakeFeed, false))
+	data.store = store
+	data.configData = &ConfigData{Feeds: []Feed{{URL: testURLOne, MaxEntries: 1}}}
+
+	assert.Nil(t, data.loadFeedData(testURLOne))
+	entries := data.safeFeedData.GetEntries(testURLOne).entries
+	assert.Len(t, entries, 1)
+	assert.Equal(t, fakeFeed.Items[0].Title, entries[0].title)
+
+	data.safeFeedData.Clear()
+	assert.Nil(t, data.loadFeedsFromStore())
+	assert.Len(t, data.safeFeedData.GetEntries(testURLOne).entries, 1)
+}
+
+func TestConfigNameReplacesFeedTitle(t *testing.T) {
+	data := createTestData(false)
+	data.configData.Feeds[0].Name = "My [Registry]"
+	data.configData.Feeds[1].Content = fullTextContent
+
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+	text, _ := ui.feedList.GetItemText(1)
+	assert.Equal(t, "My [Registry[] (2)", text)
+	assert.Equal(t, "google", data.feedName(testURLTwo))
+
+	assert.False(t, data.configData.readerMode(testURLOne))
+	assert.True(t, data.configData.readerMode(testURLTwo))
+}
+
+func TestCapEntries(t *testing.T) {
+	entries := []Entry{{guid: "a"}, {guid: "b"}, {guid: "c"}}
+	assert.Len(t, capEntries(entries, 0), 3)
+	assert.Len(t, capEntries(entries, 5), 3)
+	assert.Equal(t, []Entry{{guid: "a"}, {guid: "b"}}, capEntries(entries, 2))
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	bolt "go.etcd.io/bbolt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const storeFileName = "clacks.db"
+
+var feedsBucket = []byte("feeds")
+var entriesBucket = []byte("entries")
+var httpCacheBucket = []byte("httpcache")
+
+// storeLockTimeout how long to wait for another clacks process to finish with the store
+const storeLockTimeout = 10 * time.Second
+
+// EntryStore interface to the on disk database of every entry that has been seen
+type EntryStore interface {
+	MergeFeed(url string, feedData FeedDataModel) (FeedDataModel, error)
+	LoadFeeds() (map[string]FeedDataModel, error)
+	LoadFeed(url string) (FeedDataModel, error)
+	SetRead(url string, guids []string, read bool) error
+	SetFullContent(url string, guid string, content string) error
+	Close() error
+}
+
+// BoltStore EntryStore kept in a bbolt database, entries are keyed by feed url and item guid, the database is
+// only open for the length of each transaction so the reader and commands run from scripts can share it
+type BoltStore struct {
+	path  string
+	mutex sync.RWMutex
+}
+
+// storedEntry struct to marshall an entry into the database
+type storedEntry struct {
+	GUID        string    `json:"guid"`
+	Title       string    `json:"title"`
+	Content     string    `json:"content"`
+	FullContent string    `json:"fullContent,omitempty"`
+	URL         string    `json:"url"`
+	Author      string    `json:"author,omitempty"`
+	Published   time.Time `json:"published,omitempty"`
+	Updated     time.Time `json:"updated,omitempty"`
+	Categories  []string  `json:"categories,omitempty"`
+	Read        bool      `json:"read"`
+	FirstSeen   time.Time `json:"firstSeen"`
+	Position    int       `json:"position"`
+}
+
+// OpenBoltStore factory method opening the database at path, creating it and its directory if needed
+func OpenBoltStore(path string) (*BoltStore, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, errors.New("error creating data directory: " + err.Error())
+	}
+
+	store := &BoltStore{path: path}
+	err = store.update(func(tx *bolt.Tx) error {
+		_, bucketErr := tx.CreateBucketIfNotExists(feedsBucket)
+		if bucketErr != nil {
+			return bucketErr
+		}
+		_, bucketErr = tx.CreateBucketIfNotExists(entriesBucket)
+		if bucketErr != nil {
+			return bucketErr
+		}
+		_, bucketErr = tx.CreateBucketIfNotExists(httpCacheBucket)
+		return bucketErr
+	})
+	if err != nil {
+		return nil, errors.New("error opening entry store: " + err.Error())
+	}
+
+	return store, nil
+}
+
+// update run a read-write transaction, bbolt only lets one process at a time open the database for writing
+func (store *BoltStore) update(fn func(tx *bolt.Tx) error) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.withDB(false, func(db *bolt.DB) error {
+		return db.Update(fn)
+	})
+}
+
+// view run a read-only transaction, any number of processes can read the database at the same time
+func (store *BoltStore) view(fn func(tx *bolt.Tx) error) error {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	return store.withDB(true, func(db *bolt.DB) error {
+		return db.View(fn)
+	})
+}
+
+// open the database, run fn and close it again, waits for another process writing to the database to finish
+func (store *BoltStore) withDB(readOnly bool, fn func(db *bolt.DB) error) error {
+	db, err := bolt.Open(store.path, 0600, &bolt.Options{Timeout: storeLockTimeout, ReadOnly: readOnly})
+	if err == bolt.ErrTimeout {
+		return errors.New("the entry store is in use by another clacks process")
+	}
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return fn(db)
+}
+
+// MergeFeed save freshly fetched feed data, returns every entry stored for the feed newest first
+func (store *BoltStore) MergeFeed(url string, feedData FeedDataModel) (FeedDataModel, error) {
+	merged := FeedDataModel{name: feedData.name}
+	fetchedAt := time.Now()
+
+	err := store.update(func(tx *bolt.Tx) error {
+		putErr := tx.Bucket(feedsBucket).Put([]byte(url), []byte(feedData.name))
+		if putErr != nil {
+			return putErr
+		}
+
+		feedBucket, bucketErr := tx.Bucket(entriesBucket).CreateBucketIfNotExists([]byte(url))
+		if bucketErr != nil {
+			return bucketErr
+		}
+
+		for i, entry := range feedData.entries {
+			stored := storedEntry{FirstSeen: fetchedAt, Position: i}
+			// keep when the entry was first seen so it stays in the same place, and whether it has been read
+			existing := feedBucket.Get([]byte(entry.guid))
+			if existing != nil {
+				if jsonErr := json.Unmarshal(existing, &stored); jsonErr != nil {
+					return jsonErr
+				}
+			}
+			stored.GUID = entry.guid
+			stored.Title = entry.title
+			stored.Content = entry.content
+			// articles fetched in reader mode are kept when the feed is fetched again
+			if entry.fullContent != "" {
+				stored.FullContent = entry.fullContent
+			}
+			stored.URL = entry.url
+			stored.Author = entry.author
+			stored.Published = entry.published
+			stored.Updated = entry.updated
+			stored.Categories = entry.categories
+
+			value, jsonErr := json.Marshal(stored)
+			if jsonErr != nil {
+				return jsonErr
+			}
+			if putErr = feedBucket.Put([]byte(entry.guid), value); putErr != nil {
+				return putErr
+			}
+		}
+
+		entries, readErr := readEntries(feedBucket)
+		merged.entries = entries
+		return readErr
+	})
+	if err != nil {
+		return FeedDataModel{}, errors.New("error saving feed to entry store: " + err.Error())
+	}
+
+	return merged, nil
+}
+
+// LoadFeeds read every feed in the database, used to populate the ui before any network fetch
+func (store *BoltStore) LoadFeeds() (map[string]FeedDataModel, error) {
+	feeds := make(map[string]FeedDataModel)
+
+	err := store.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(feedsBucket).ForEach(func(url, _ []byte) error {
+			feedData, readErr := readFeed(tx, url)
+			feeds[string(url)] = feedData
+			return readErr
+		})
+	})
+	if err != nil {
+		return nil, errors.New("error reading entry store: " + err.Error())
+	}
+
+	return feeds, nil
+}
+
+// LoadFeed read a single feed from the database, a feed that was never stored has no entries
+func (store *BoltStore) LoadFeed(url string) (FeedDataModel, error) {
+	var feedData FeedDataModel
+	err := store.view(func(tx *bolt.Tx) error {
+		var readErr error
+		feedData, readErr = readFeed(tx, []byte(url))
+		return readErr
+	})
+	if err != nil {
+		return FeedDataModel{}, errors.New("error reading entry store: " + err.Error())
+	}
+	return feedData, nil
+}
+
+// read the name and entries of a feed
+func readFeed(tx *bolt.Tx, url []byte) (FeedDataModel, error) {
+	feedData := FeedDataModel{name: string(tx.Bucket(feedsBucket).Get(url))}
+	feedBucket := tx.Bucket(entriesBucket).Bucket(url)
+	if feedBucket == nil {
+		return feedData, nil
+	}
+	entries, err := readEntries(feedBucket)
+	feedData.entries = entries
+	return feedData, err
+}
+
+// SetRead set the read state of entries of a feed
+func (store *BoltStore) SetRead(url string, guids []string, read bool) error {
+	err := store.updateEntries(url, guids, func(stored *storedEntry) {
+		stored.Read = read
+	})
+	if err != nil {
+		return errors.New("error saving read state to entry store: " + err.Error())
+	}
+	return nil
+}
+
+// SetFullContent save the article fetched for an entry of a feed
+func (store *BoltStore) SetFullContent(url string, guid string, content string) error {
+	err := store.updateEntries(url, []string{guid}, func(stored *storedEntry) {
+		stored.FullContent = content
+	})
+	if err != nil {
+		return errors.New("error saving article to entry store: " + err.Error())
+	}
+	return nil
+}
+
+// updateEntries apply update to the stored entries of a feed with matching guids
+func (store *BoltStore) updateEntries(url string, guids []string, update func(stored *storedEntry)) error {
+	return store.update(func(tx *bolt.Tx) error {
+		feedBucket := tx.Bucket(entriesBucket).Bucket([]byte(url))
+		if feedBucket == nil {
+			return nil
+		}
+		for _, guid := range guids {
+			value := feedBucket.Get([]byte(guid))
+			if value == nil {
+				continue
+			}
+			var stored storedEntry
+			if jsonErr := json.Unmarshal(value, &stored); jsonErr != nil {
+				return jsonErr
+			}
+			update(&stored)
+			value, jsonErr := json.Marshal(stored)
+			if jsonErr != nil {
+				return jsonErr
+			}
+			if putErr := feedBucket.Put([]byte(guid), value); putErr != nil {
+				return putErr
+			}
+		}
+		return nil
+	})
+}
+
+// GetCachedResponse look up the last response downloaded for a feed
+func (store *BoltStore) GetCachedResponse(url string) (CachedResponse, bool, error) {
+	var cached CachedResponse
+	found := false
+
+	err := store.view(func(tx *bolt.Tx) error {
+		value := tx.Bucket(httpCacheBucket).Get([]byte(url))
+		if value == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(value, &cached)
+	})
+	if err != nil {
+		return CachedResponse{}, false, errors.New("error reading http cache: " + err.Error())
+	}
+
+	return cached, found, nil
+}
+
+// SetCachedResponse save the response downloaded for a feed
+func (store *BoltStore) SetCachedResponse(url string, response CachedResponse) error {
+	value, err := json.Marshal(response)
+	if err == nil {
+		err = store.update(func(tx *bolt.Tx) error {
+			return tx.Bucket(httpCacheBucket).Put([]byte(url), value)
+		})
+	}
+	if err != nil {
+		return errors.New("error saving http cache: " + err.Error())
+	}
+	return nil
+}
+
+// Close nothing to close, the database is only open during transactions
+func (store *BoltStore) Close() error {
+	return nil
+}
+
+// read entries of a feed bucket, newest first and in publisher order within the same fetch
+func readEntries(feedBucket *bolt.Bucket) ([]Entry, error) {
+	var stored []storedEntry
+	err := feedBucket.ForEach(func(_, value []byte) error {
+		var entry storedEntry
+		if jsonErr := json.Unmarshal(value, &entry); jsonErr != nil {
+			return jsonErr
+		}
+		stored = append(stored, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(stored, func(i, j int) bool {
+		if !stored[i].FirstSeen.Equal(stored[j].FirstSeen) {
+			return stored[i].FirstSeen.After(stored[j].FirstSeen)
+		}
+		return stored[i].Position < stored[j].Position
+	})
+
+	entries := make([]Entry, len(stored))
+	for i, entry := range stored {
+		entries[i] = Entry{
+			guid:        entry.GUID,
+			title:       entry.Title,
+			content:     entry.Content,
+			fullContent: entry.FullContent,
+			url:         entry.URL,
+			author:      entry.Author,
+			published:   entry.Published,
+			updated:     entry.Updated,
+			categories:  entry.Categories,
+			read:        entry.Read,
+		}
+	}
+	return entries, nil
+}
+
+// defaultDataDir directory the entry store is kept in, follows the XDG base directory spec
+func defaultDataDir() string {
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, "clacks")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	return filepath.Join(home, ".local", "share", "clacks")
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOpenBoltStoreCreatesDataDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", storeFileName)
+
+	store, err := OpenBoltStore(path)
+	assert.Nil(t, err)
+	defer store.Close()
+
+	_, err = os.Stat(path)
+	assert.Nil(t, err)
+}
+
+func TestMergeFeedKeepsEntriesNoLongerPublished(t *testing.T) {
+	store, err := OpenBoltStore(filepath.Join(t.TempDir(), storeFileName))
+	assert.Nil(t, err)
+	defer store.Close()
+
+	firstFetch := FeedDataModel{name: "registry", entries: []Entry{
+		{guid: "2", title: "two"},
+		{guid: "1", title: "one"},
+	}}
+	merged, err := store.MergeFeed(testURLOne, firstFetch)
+	assert.Nil(t, err)
+	assert.Equal(t, firstFetch.entries, merged.entries)
+
+	secondFetch := FeedDataModel{name: "registry", entries: []Entry{
+		{guid: "3", title: "three"},
+		{guid: "2", title: "two updated"},
+	}}
+	merged, err = store.MergeFeed(testURLOne, secondFetch)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "registry", merged.name)
+	assert.Equal(t, []Entry{
+		{guid: "3", title: "three"},
+		{guid: "2", title: "two updated"},
+		{guid: "1", title: "one"},
+	}, merged.entries)
+}
+
+func TestMergeFeedKeepsMetadata(t *testing.T) {
+	store, err := OpenBoltStore(filepath.Join(t.TempDir(), storeFileName))
+	assert.Nil(t, err)
+	defer store.Close()
+
+	published := time.Date(2021, time.May, 3, 9, 30, 0, 0, time.UTC)
+	entry := Entry{guid: "1", title: "one", author: "Simon", published: published,
+		updated: published.Add(time.Hour), categories: []string{"bofh", "humour"}}
+
+	merged, err := store.MergeFeed(testURLOne, FeedDataModel{name: "registry", entries: []Entry{entry}})
+	assert.Nil(t, err)
+
+	assert.Equal(t, "Simon", merged.entries[0].author)
+	assert.True(t, published.Equal(merged.entries[0].published))
+	assert.True(t, published.Add(time.Hour).Equal(merged.entries[0].updated))
+	assert.Equal(t, []string{"bofh", "humour"}, merged.entries[0].categories)
+}
+
+func TestLoadFeedsSurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), storeFileName)
+	store, err := OpenBoltStore(path)
+	assert.Nil(t, err)
+
+	_, err = store.MergeFeed(testURLOne, createFakeFeedDataModel("registry", testURLOne))
+	assert.Nil(t, err)
+	_, err = store.MergeFeed(testURLTwo, createFakeFeedDataModel("google", testURLTwo))
+	assert.Nil(t, err)
+	assert.Nil(t, store.Close())
+
+	store, err = OpenBoltStore(path)
+	assert.Nil(t, err)
+	defer store.Close()
+
+	feeds, err := store.LoadFeeds()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(feeds))
+	assert.Equal(t, "google", feeds[testURLTwo].name)
+	assert.Equal(t, "registry fake title one", feeds[testURLOne].entries[0].title)
+	assert.Equal(t, "registry fake content two", feeds[testURLOne].entries[1].content)
+}
+
+func TestStoreCanBeSharedWithAnotherProcess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), storeFileName)
+	reader, err := OpenBoltStore(path)
+	assert.Nil(t, err)
+	defer reader.Close()
+	command, err := OpenBoltStore(path)
+	assert.Nil(t, err)
+	defer command.Close()
+
+	_, err = reader.MergeFeed(testURLOne, createFakeFeedDataModel("registry", testURLOne))
+	assert.Nil(t, err)
+	feeds, err := command.LoadFeeds()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(feeds[testURLOne].entries))
+
+	err = command.SetRead(testURLOne, []string{testURLOne + "/one"}, true)
+	assert.Nil(t, err)
+	feedData, err := reader.LoadFeed(testURLOne)
+	assert.Nil(t, err)
+	assert.True(t, feedData.entries[0].read)
+}
+
+func TestLoadFeed(t *testing.T) {
+	store, err := OpenBoltStore(filepath.Join(t.TempDir(), storeFileName))
+	assert.Nil(t, err)
+	defer store.Close()
+
+	_, err = store.MergeFeed(testURLOne, createFakeFeedDataModel("registry", testURLOne))
+	assert.Nil(t, err)
+
+	feedData, err := store.LoadFeed(testURLOne)
+	assert.Nil(t, err)
+	assert.Equal(t, "registry", feedData.name)
+	assert.Equal(t, "registry fake title one", feedData.entries[0].title)
+
+	feedData, err = store.LoadFeed(testURLTwo)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(feedData.entries))
+}
+
+func TestDefaultDataDirUsesXDGDataHome(t *testing.T) {
+	defer restoreEnv("XDG_DATA_HOME")()
+	defer restoreEnv("HOME")()
+
+	_ = os.Setenv("XDG_DATA_HOME", "/tmp/xdg")
+	assert.Equal(t, filepath.Join("/tmp/xdg", "clacks"), defaultDataDir())
+
+	_ = os.Unsetenv("XDG_DATA_HOME")
+	_ = os.Setenv("HOME", "/home/clacks")
+	assert.Equal(t, filepath.Join("/home/clacks", ".local", "share", "clacks"), defaultDataDir())
+}
+
+// restoreEnv returns a func that puts an environment variable back to its current value
+func restoreEnv(key string) func() {
+	value, set := os.LookupEnv(key)
+	return func() {
+		if set {
+			_ = os.Setenv(key, value)
+		} else {
+			_ = os.Unsetenv(key)
+		}
+	}
+}
+
+func TestSetReadIsKeptWhenFeedIsFetchedAgain(t *testing.T) {
+	store, err := OpenBoltStore(filepath.Join(t.TempDir(), storeFileName))
+	assert.Nil(t, err)
+	defer store.Close()
+
+	feedData := createFakeFeedDataModel("registry", testURLOne)
+	_, err = store.MergeFeed(testURLOne, feedData)
+	assert.Nil(t, err)
+
+	err = store.SetRead(testURLOne, []string{testURLOne + "/two", "unknown"}, true)
+	assert.Nil(t, err)
+
+	err = store.SetRead(testURLTwo, []string{testURLTwo + "/one"}, true)
+	assert.Nil(t, err)
+
+	merged, err := store.MergeFeed(testURLOne, feedData)
+	assert.Nil(t, err)
+	assert.False(t, merged.entries[0].read)
+	assert.True(t, merged.entries[1].read)
+}
@@ -84,15 +84,33 @@ func (app *StubbedApp) QueueUpdateDraw(f func()) *tview.Application {
 	return nil
 }
 
+// QueuedUpdateDraws returns a copy of the functions passed to QueueUpdateDraw so far
+func (app *StubbedApp) QueuedUpdateDraws() []func() {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	draws := make([]func(), len(app.UpdateDraws))
+	copy(draws, app.UpdateDraws)
+	return draws
+}
+
 // GetInputCapture does nothing
 func (app *StubbedApp) GetInputCapture() func(event *tcell.EventKey) *tcell.EventKey {
 	return nil
 }
 
-// StubbedParser holds fake data and throws error when bool is set true
+// SetAfterDrawFunc does nothing
+func (app *StubbedApp) SetAfterDrawFunc(_ func(screen tcell.Screen)) *tview.Application {
+	return nil
+}
+
+// StubbedParser holds fake data and throws error when bool is set true or the url is in failingURLs,
+// it counts how many times each url is parsed
 type StubbedParser struct {
-	fakeFeed  *gofeed.Feed
-	withError bool
+	fakeFeed    *gofeed.Feed
+	withError   bool
+	failingURLs map[string]bool
+	mutex       sync.Mutex
+	calls       map[string]int
 }
 
 func createStubbedParser(fakeFeed *gofeed.Feed, withError bool) FeedParser {
@@ -105,14 +123,28 @@ func createStubbedParser(fakeFeed *gofeed.Feed, withError bool) FeedParser {
 }
 
 // ParseURL stub
-func (parser *StubbedParser) ParseURL(_ string) (feed *gofeed.Feed, err error) {
-	if parser.withError {
+func (parser *StubbedParser) ParseURL(url string, _ RequestSettings) (feed *gofeed.Feed, err error) {
+	parser.mutex.Lock()
+	if parser.calls == nil {
+		parser.calls = make(map[string]int)
+	}
+	parser.calls[url]++
+	parser.mutex.Unlock()
+
+	if parser.withError || parser.failingURLs[url] {
 		return nil, errors.New("stubbed parser error")
 	}
 
 	return parser.fakeFeed, nil
 }
 
+// callCount how many times url has been parsed
+func (parser *StubbedParser) callCount(url string) int {
+	parser.mutex.Lock()
+	defer parser.mutex.Unlock()
+	return parser.calls[url]
+}
+
 // StubbedBrowserLauncher does nothing and throws error when bool is set true
 type StubbedBrowserLauncher struct {
 	withError bool
@@ -126,6 +158,45 @@ func (sbl StubbedBrowserLauncher) OpenDefault(_ string) error {
 	return nil
 }
 
+// RecordingBrowserLauncher remembers every url it is asked to open
+type RecordingBrowserLauncher struct {
+	opened []string
+}
+
+// OpenDefault records url instead of opening it
+func (rbl *RecordingBrowserLauncher) OpenDefault(url string) error {
+	rbl.opened = append(rbl.opened, url)
+	return nil
+}
+
+// StubbedArticleFetcher returns the same article for every page and throws error when bool is set true
+type StubbedArticleFetcher struct {
+	article   string
+	withError bool
+}
+
+// FetchArticle returns the stubbed article
+func (saf StubbedArticleFetcher) FetchArticle(_ string, _ RequestSettings) (string, error) {
+	if saf.withError {
+		return "", errors.New("stubbed article fetcher error")
+	}
+	return saf.article, nil
+}
+
+// StubbedFeedDiscoverer finds the same feeds on every site and throws error when bool is set true
+type StubbedFeedDiscoverer struct {
+	feeds     []DiscoveredFeed
+	withError bool
+}
+
+// DiscoverFeeds returns the stubbed feeds
+func (sfd StubbedFeedDiscoverer) DiscoverFeeds(_ string) ([]DiscoveredFeed, error) {
+	if sfd.withError {
+		return nil, errors.New("stubbed feed discoverer error")
+	}
+	return sfd.feeds, nil
+}
+
 // StubbedBuffer stub for buffer to get ioutils.readall to throw an error
 type StubbedBuffer struct {
 }
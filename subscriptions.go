@@ -0,0 +1,258 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/mmcdole/gofeed"
+	"github.com/rivo/tview"
+	"strings"
+)
+
+// width of the forms adding and renaming feeds
+const feedFormWidth = 70
+
+// save a changed config to the config file and start using it, the config in use is left alone if it can't be saved
+func (ui *UI) saveConfig(config *ConfigData) error {
+	err := writeConfigFile(ui.configFileName, config)
+	if err != nil {
+		return err
+	}
+	ui.data.setConfig(config)
+	return nil
+}
+
+// feed of the selected row of the feed list, the all feeds row and folders aren't feeds
+func (ui *UI) selectedFeed() (Feed, bool) {
+	return ui.data.configData.feed(ui.getSelectedFeedURL())
+}
+
+// check the feed at url can be parsed, returns the feed's title
+func (data *Data) checkFeed(url string) (string, error) {
+	feed, err := data.parser.ParseURL(url, RequestSettings{})
+	if err == errNotModified && data.store != nil {
+		// a feed that was removed and is being added back, it was downloaded before and is unchanged
+		stored, storeErr := data.store.LoadFeed(url)
+		if storeErr == nil && len(stored.entries) > 0 {
+			feed, err = &gofeed.Feed{Title: stored.name}, nil
+		}
+	}
+	if err != nil {
+		return "", errors.New("error loading feed: " + err.Error())
+	}
+	if strings.TrimSpace(feed.Title) == "" {
+		return url, nil
+	}
+	return strings.TrimSpace(feed.Title), nil
+}
+
+// show a form centered over the feed page, keys typed into it aren't shortcuts
+func (ui *UI) showFeedForm(form *tview.Form, height int) {
+	ui.previousFocus = ui.app.GetFocus()
+	form.SetBorder(true)
+	form.SetButtonBackgroundColor(color(ui.theme.MenuBackground)).SetButtonTextColor(color(ui.theme.MenuText))
+	form.SetCancelFunc(ui.closeFeedForm)
+
+	page := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, height, 0, true).
+			AddItem(nil, 0, 1, false), feedFormWidth, 0, true).
+		AddItem(nil, 0, 1, false)
+	ui.pages.AddPage(feedFormPage, page, true, true)
+	ui.app.SetFocus(form)
+}
+
+// close the form adding or renaming a feed
+func (ui *UI) closeFeedForm() {
+	ui.pages.SwitchToPage(feedPage)
+	ui.pages.RemovePage(feedFormPage)
+	ui.app.SetFocus(ui.previousFocus)
+}
+
+// create the form adding a feed, the url is checked by parsing the feed and its title shown before the
+// feed can be saved, pressing Add a second time saves it, the url of a web site offers the feeds found on it
+func (ui *UI) createAddFeedPage() *tview.Form {
+	form := tview.NewForm()
+	form.SetTitle("Add Feed")
+	form.AddInputField("URL", "", 0, nil, nil)
+	form.AddInputField("Name", "", 0, nil, nil)
+	form.AddInputField("Category", "", 0, nil, nil)
+
+	checkedURL := ""
+	form.AddButton("Add", func() {
+		url := strings.TrimSpace(form.GetFormItemByLabel("URL").(*tview.InputField).GetText())
+		switch {
+		case url == "":
+			form.SetTitle("Add Feed - enter the url of a feed")
+		case ui.data.configData.hasFeed(url):
+			form.SetTitle("Add Feed - " + tview.Escape(url) + " is already in your feeds")
+		case url != checkedURL:
+			form.SetTitle("Add Feed - checking " + tview.Escape(url))
+			go ui.checkNewFeed(form, url, func() { checkedURL = url })
+		default:
+			ui.addFeed(Feed{
+				URL:      url,
+				Name:     strings.TrimSpace(form.GetFormItemByLabel("Name").(*tview.InputField).GetText()),
+				Category: strings.TrimSpace(form.GetFormItemByLabel("Category").(*tview.InputField).GetText()),
+			})
+		}
+	})
+	form.AddButton("Cancel", ui.closeFeedForm)
+
+	ui.showFeedForm(form, 13)
+	return form
+}
+
+// parse the feed at url for the add feed form and show its title, checked is called when it parses,
+// when url isn't a feed the feeds found on the web site at url are offered instead
+func (ui *UI) checkNewFeed(form *tview.Form, url string, checked func()) {
+	title, err := ui.data.checkFeed(url)
+	var discovered []DiscoveredFeed
+	if err != nil && ui.data.discoverer != nil {
+		ui.app.QueueUpdateDraw(func() {
+			form.SetTitle("Add Feed - looking for feeds on the site " + tview.Escape(url))
+		})
+		discovered, _ = ui.data.discoverer.DiscoverFeeds(url)
+	}
+	ui.app.QueueUpdateDraw(func() {
+		if len(discovered) > 0 {
+			ui.offerDiscoveredFeeds(form, discovered)
+			return
+		}
+		if err != nil {
+			form.SetTitle("Add Feed - " + tview.Escape(err.Error()))
+			return
+		}
+		checked()
+		form.SetTitle("Add Feed - found " + tview.Escape(title) + ", press Add again to save it")
+	})
+}
+
+// let the feeds found on a web site be chosen in the add feed form, the chosen feed's url replaces the url
+// typed in and is checked like any other when Add is pressed
+func (ui *UI) offerDiscoveredFeeds(form *tview.Form, feeds []DiscoveredFeed) {
+	urlField := form.GetFormItemByLabel("URL").(*tview.InputField)
+	options := make([]string, len(feeds))
+	for i, feed := range feeds {
+		options[i] = tview.Escape(feed.URL)
+		if feed.Title != "" {
+			options[i] = tview.Escape(feed.Title + " - " + feed.URL)
+		}
+	}
+
+	if i := form.GetFormItemIndex("Found"); i >= 0 {
+		form.RemoveFormItem(i)
+	}
+	form.AddDropDown("Found", options, 0, func(_ string, i int) {
+		if i >= 0 {
+			urlField.SetText(feeds[i].URL)
+		}
+	})
+	urlField.SetText(feeds[0].URL)
+	if len(feeds) == 1 {
+		form.SetTitle("Add Feed - found a feed on the site, press Add to check it")
+		return
+	}
+	form.SetTitle(fmt.Sprintf("Add Feed - found %d feeds on the site, choose one and press Add", len(feeds)))
+}
+
+// add a feed to the config, select its row and fetch it
+func (ui *UI) addFeed(feed Feed) {
+	config := ui.data.configData.copy()
+	config.addFeeds([]Feed{feed})
+	err := ui.saveConfig(config)
+	if err != nil {
+		ui.createMessagePage(err.Error())
+		return
+	}
+
+	ui.closeFeedForm()
+	ui.populateFeedList()
+	if i := ui.feedRowIndex(feed.URL); i >= 0 {
+		ui.feedList.SetCurrentItem(i)
+	}
+	go ui.data.fetchFeeds([]string{feed.URL}, ui.updateFeedRow)
+}
+
+// create the form renaming the selected feed, an empty name goes back to the feed's own title
+func (ui *UI) createRenameFeedPage() *tview.Form {
+	feed, ok := ui.selectedFeed()
+	if !ok {
+		ui.createMessagePage("Select a feed to rename")
+		return nil
+	}
+
+	form := tview.NewForm()
+	form.SetTitle("Rename " + tview.Escape(ui.feedName(feed.URL)))
+	form.AddInputField("Name", feed.Name, 0, nil, nil)
+	form.AddButton("Save", func() {
+		ui.renameFeed(feed.URL, strings.TrimSpace(form.GetFormItemByLabel("Name").(*tview.InputField).GetText()))
+	})
+	form.AddButton("Cancel", ui.closeFeedForm)
+
+	ui.showFeedForm(form, 7)
+	return form
+}
+
+// give the feed at url a name in the config and update the rows showing it
+func (ui *UI) renameFeed(url string, name string) {
+	config := ui.data.configData.copy()
+	for i := range config.Feeds {
+		if config.Feeds[i].URL == url {
+			config.Feeds[i].Name = name
+		}
+	}
+	err := ui.saveConfig(config)
+	if err != nil {
+		ui.createMessagePage(err.Error())
+		return
+	}
+
+	ui.closeFeedForm()
+	ui.updateReadState()
+}
+
+// ask whether to remove the selected feed from the config
+func (ui *UI) createDeleteFeedPage() *tview.Modal {
+	feed, ok := ui.selectedFeed()
+	if !ok {
+		ui.createMessagePage("Select a feed to remove")
+		return nil
+	}
+	ui.previousFocus = ui.app.GetFocus()
+
+	deleteBox := ui.createOverlayModal(deleteFeedPage, "Remove "+tview.Escape(ui.feedName(feed.URL))+" from your feeds?",
+		[]string{"Remove", "Cancel"},
+		func(buttonIndex int, buttonLabel string) {
+			ui.pages.SwitchToPage(feedPage)
+			ui.pages.RemovePage(deleteFeedPage)
+			ui.app.SetFocus(ui.previousFocus)
+			if buttonLabel == "Remove" {
+				ui.deleteFeed(feed.URL)
+			}
+		})
+	ui.app.SetFocus(deleteBox)
+	return deleteBox
+}
+
+// remove the feed at url from the config and the feed list, the entry store keeps its entries
+func (ui *UI) deleteFeed(url string) {
+	config := ui.data.configData.copy()
+	// an empty list rather than nil so the config file still lists its feeds once the last one is removed
+	feeds := []Feed{}
+	for _, feed := range config.Feeds {
+		if feed.URL != url {
+			feeds = append(feeds, feed)
+		}
+	}
+	config.Feeds = feeds
+	err := ui.saveConfig(config)
+	if err != nil {
+		ui.createMessagePage(err.Error())
+		return
+	}
+
+	ui.data.safeFeedData.Remove(url)
+	ui.populateFeedList()
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// ui on a stubbed app saving its config to a temp dir, returns the config file
+func setupSubscriptions(t *testing.T) (*UI, *StubbedApp, string) {
+	data := createTestData(false)
+	app := CreateStubbedApp(false).(*StubbedApp)
+	ui := CreateUI(app, data)
+	ui.configFileName = filepath.Join(t.TempDir(), configFileName)
+	ui.setupLists()
+	return ui, app, ui.configFileName
+}
+
+// press a button of a form
+func pressButton(form *tview.Form, label string) {
+	form.GetButton(form.GetButtonIndex(label)).InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, 0), nil)
+}
+
+// config saved to fileName
+func savedConfig(t *testing.T, fileName string) *ConfigData {
+	data := NewData(nil)
+	assert.Nil(t, data.loadJSONConfig(fileName))
+	return data.configData
+}
+
+func TestAddFeedChecksFeedBeforeSaving(t *testing.T) {
+	ui, app, fileName := setupSubscriptions(t)
+
+	form := ui.createAddFeedPage()
+	frontPage, _ := ui.pages.GetFrontPage()
+	assert.Equal(t, feedFormPage, frontPage)
+
+	pressButton(form, "Add")
+	assert.Equal(t, "Add Feed - enter the url of a feed", form.GetTitle())
+
+	form.GetFormItemByLabel("URL").(*tview.InputField).SetText(testURLOne)
+	pressButton(form, "Add")
+	assert.Equal(t, "Add Feed - theregistry.com is already in your feeds", form.GetTitle())
+
+	form.GetFormItemByLabel("URL").(*tview.InputField).SetText("example.com/feed")
+	form.GetFormItemByLabel("Category").(*tview.InputField).SetText("Tech")
+	queued := len(app.QueuedUpdateDraws())
+	pressButton(form, "Add")
+	assert.Equal(t, "Add Feed - checking example.com/feed", form.GetTitle())
+	assert.Eventually(t, func() bool { return len(app.QueuedUpdateDraws()) > queued }, time.Second, 10*time.Millisecond)
+	app.QueuedUpdateDraws()[queued]()
+	assert.Equal(t, "Add Feed - found Test Feed Title From Parser, press Add again to save it", form.GetTitle())
+
+	queued = len(app.QueuedUpdateDraws())
+	pressButton(form, "Add")
+	assert.Equal(t, Feed{URL: "example.com/feed", Category: "Tech"}, savedConfig(t, fileName).Feeds[2])
+	assert.Len(t, ui.data.configData.Feeds, 3)
+	frontPage, _ = ui.pages.GetFrontPage()
+	assert.Equal(t, feedPage, frontPage)
+	assert.Equal(t, "example.com/feed", ui.getSelectedFeedURL())
+
+	// the new feed is fetched in the background
+	assert.Eventually(t, func() bool { return len(app.QueuedUpdateDraws()) > queued }, time.Second, 10*time.Millisecond)
+	assert.Len(t, ui.data.safeFeedData.GetEntries("example.com/feed").entries, 2)
+}
+
+func TestAddFeedShowsParseErrors(t *testing.T) {
+	ui, app, fileName := setupSubscriptions(t)
+	ui.data.parser = createStubbedParser(nil, true)
+
+	form := ui.createAddFeedPage()
+	form.GetFormItemByLabel("URL").(*tview.InputField).SetText("example.com")
+	ui.checkNewFeed(form, "example.com", func() { t.Fail() })
+	draws := app.QueuedUpdateDraws()
+	draws[len(draws)-1]()
+	assert.Equal(t, "Add Feed - error loading feed: stubbed parser error", form.GetTitle())
+	assert.NoFileExists(t, fileName)
+}
+
+func TestRenameFeed(t *testing.T) {
+	ui, _, fileName := setupSubscriptions(t)
+
+	ui.feedList.SetCurrentItem(0)
+	assert.Nil(t, ui.createRenameFeedPage())
+	frontPage, _ := ui.pages.GetFrontPage()
+	assert.Equal(t, messagePage, frontPage)
+
+	ui.feedList.SetCurrentItem(1)
+	form := ui.createRenameFeedPage()
+	assert.Equal(t, "Rename registry", form.GetTitle())
+	form.GetFormItemByLabel("Name").(*tview.InputField).SetText(" The Register ")
+	pressButton(form, "Save")
+
+	assert.Equal(t, "The Register", savedConfig(t, fileName).Feeds[0].Name)
+	text, _ := ui.feedList.GetItemText(1)
+	assert.Equal(t, "The Register (2)", text)
+
+	form = ui.createRenameFeedPage()
+	assert.Equal(t, "The Register", form.GetFormItemByLabel("Name").(*tview.InputField).GetText())
+	form.GetFormItemByLabel("Name").(*tview.InputField).SetText("")
+	pressButton(form, "Save")
+	text, _ = ui.feedList.GetItemText(1)
+	assert.Equal(t, "registry (2)", text)
+}
+
+func TestDeleteFeed(t *testing.T) {
+	ui, _, fileName := setupSubscriptions(t)
+
+	ui.feedList.SetCurrentItem(1)
+	deleteBox := ui.createDeleteFeedPage()
+	frontPage, _ := ui.pages.GetFrontPage()
+	assert.Equal(t, deleteFeedPage, frontPage)
+	// the stubbed app doesn't move focus so give the modal's buttons focus before pressing Remove
+	var focus func(p tview.Primitive)
+	focus = func(p tview.Primitive) { p.Focus(focus) }
+	deleteBox.Focus(focus)
+	deleteBox.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, 0), nil)
+
+	assert.Equal(t, []Feed{{URL: testURLTwo}}, savedConfig(t, fileName).Feeds)
+	assert.Equal(t, -1, ui.feedRowIndex(testURLOne))
+	assert.Equal(t, 2, ui.feedList.GetItemCount())
+	assert.Empty(t, ui.data.safeFeedData.GetEntries(testURLOne).entries)
+	frontPage, _ = ui.pages.GetFrontPage()
+	assert.Equal(t, feedPage, frontPage)
+}
+
+func TestDeleteEveryFeedThenRefresh(t *testing.T) {
+	ui, app, fileName := setupSubscriptions(t)
+
+	ui.deleteFeed(testURLOne)
+	ui.deleteFeed(testURLTwo)
+
+	saved, err := ioutil.ReadFile(fileName)
+	assert.Nil(t, err)
+	assert.Contains(t, string(saved), `"feeds": []`)
+	assert.Equal(t, 1, ui.feedList.GetItemCount())
+
+	ui.refreshAllFeeds()
+	for _, f := range app.QueuedUpdateDraws() {
+		f()
+	}
+	frontPage, _ := ui.pages.GetFrontPage()
+	assert.Equal(t, feedPage, frontPage)
+
+	// the next launch loads the saved config and has nothing to fetch
+	data := NewData(nil)
+	assert.Nil(t, data.loadJSONConfig(fileName))
+	assert.Nil(t, data.loadDataFromFeeds(nil))
+}
+
+func TestFailingToSaveConfigKeepsConfig(t *testing.T) {
+	ui, _, _ := setupSubscriptions(t)
+	// the config can't be written inside a file
+	assert.Nil(t, writeConfigFile(ui.configFileName, &ConfigData{}))
+	ui.configFileName = filepath.Join(ui.configFileName, configFileName)
+
+	ui.feedList.SetCurrentItem(1)
+	ui.deleteFeed(testURLOne)
+	assert.Len(t, ui.data.configData.Feeds, 2)
+	frontPage, _ := ui.pages.GetFrontPage()
+	assert.Equal(t, messagePage, frontPage)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"os"
+	"reflect"
+)
+
+// defaultTheme theme used when the config doesn't name one
+const defaultTheme = "dark"
+
+// Theme colors of the interface as tcell color names or #rrggbb values, unread entries are shown in bold and
+// are only colored when the theme sets an unread color
+type Theme struct {
+	Background         string `json:"background,omitempty"`
+	Text               string `json:"text,omitempty"`
+	Border             string `json:"border,omitempty"`
+	Focus              string `json:"focus,omitempty"`
+	SelectedText       string `json:"selected_text,omitempty"`
+	SelectedBackground string `json:"selected_background,omitempty"`
+	Unread             string `json:"unread,omitempty"`
+	New                string `json:"new,omitempty"`
+	Muted              string `json:"muted,omitempty"`
+	Accent             string `json:"accent,omitempty"`
+	Highlight          string `json:"highlight,omitempty"`
+	MenuText           string `json:"menu_text,omitempty"`
+	MenuBackground     string `json:"menu_background,omitempty"`
+	ModalText          string `json:"modal_text,omitempty"`
+	ModalBackground    string `json:"modal_background,omitempty"`
+	Error              string `json:"error,omitempty"`
+	reverseSelection   bool
+}
+
+// builtInThemes themes that can be named in the config without defining them
+var builtInThemes = map[string]Theme{
+	"dark": {
+		Background: "black", Text: "white", Border: "white", Focus: "blue",
+		SelectedText: "black", SelectedBackground: "white",
+		New: "green", Muted: "gray", Accent: "darkcyan", Highlight: "yellow",
+		MenuText: "white", MenuBackground: "blue", ModalText: "white", ModalBackground: "blue", Error: "red",
+	},
+	"light": {
+		Background: "white", Text: "black", Border: "gray", Focus: "blue",
+		SelectedText: "white", SelectedBackground: "blue",
+		New: "darkgreen", Muted: "gray", Accent: "teal", Highlight: "purple",
+		MenuText: "white", MenuBackground: "blue", ModalText: "black", ModalBackground: "silver", Error: "maroon",
+	},
+	"high-contrast": {
+		Background: "black", Text: "white", Border: "white", Focus: "yellow",
+		SelectedText: "black", SelectedBackground: "yellow",
+		Unread: "white", New: "lime", Muted: "white", Accent: "aqua", Highlight: "fuchsia",
+		MenuText: "black", MenuBackground: "yellow", ModalText: "white", ModalBackground: "black", Error: "red",
+	},
+}
+
+// monochromeTheme theme used when NO_COLOR is set, the terminal's own colors are used throughout
+// and the selected rows are shown in reverse
+var monochromeTheme = Theme{
+	Background: "default", Text: "default", Border: "default", Focus: "default",
+	SelectedText: "default", SelectedBackground: "default",
+	New: "default", Muted: "default", Accent: "default", Highlight: "default",
+	MenuText: "default", MenuBackground: "default", ModalText: "default", ModalBackground: "default", Error: "default",
+	reverseSelection: true,
+}
+
+// theme named in the config, themes defined in the config start from the dark theme and can replace the built in
+// themes, NO_COLOR in the environment turns off colors whatever the config says
+func (config *ConfigData) theme() (Theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return monochromeTheme, nil
+	}
+
+	name := config.Theme
+	if name == "" {
+		name = defaultTheme
+	}
+
+	theme, ok := config.Themes[name]
+	if ok {
+		theme = builtInThemes[defaultTheme].with(theme)
+	} else if theme, ok = builtInThemes[name]; !ok {
+		return Theme{}, errors.New("error unknown theme " + name)
+	}
+
+	err := theme.check(name)
+	if err != nil {
+		return Theme{}, err
+	}
+	return theme, nil
+}
+
+// theme with the colors that overrides sets replaced
+func (theme Theme) with(overrides Theme) Theme {
+	themeValue := reflect.ValueOf(&theme).Elem()
+	overridesValue := reflect.ValueOf(overrides)
+	for i := 0; i < themeValue.NumField(); i++ {
+		if field := overridesValue.Field(i); field.Kind() == reflect.String && field.String() != "" {
+			themeValue.Field(i).SetString(field.String())
+		}
+	}
+	return theme
+}
+
+// check every color of the theme is one tcell knows
+func (theme Theme) check(name string) error {
+	themeValue := reflect.ValueOf(theme)
+	for i := 0; i < themeValue.NumField(); i++ {
+		field := themeValue.Field(i)
+		if field.Kind() != reflect.String || field.String() == "" || field.String() == "default" {
+			continue
+		}
+		if tcell.GetColor(field.String()) == tcell.ColorDefault {
+			return errors.New("error unknown color " + field.String() + " in theme " + name)
+		}
+	}
+	return nil
+}
+
+// color the name of a color of the theme stands for
+func color(name string) tcell.Color {
+	return tcell.GetColor(name)
+}
+
+// styles the theme's colors as the tview styles primitives are created with
+func (theme Theme) styles() tview.Theme {
+	return tview.Theme{
+		PrimitiveBackgroundColor:    color(theme.Background),
+		ContrastBackgroundColor:     color(theme.ModalBackground),
+		MoreContrastBackgroundColor: color(theme.MenuBackground),
+		BorderColor:                 color(theme.Border),
+		TitleColor:                  color(theme.Text),
+		GraphicsColor:               color(theme.Border),
+		PrimaryTextColor:            color(theme.Text),
+		SecondaryTextColor:          color(theme.Highlight),
+		TertiaryTextColor:           color(theme.New),
+		InverseTextColor:            color(theme.Focus),
+		ContrastSecondaryTextColor:  color(theme.Accent),
+	}
+}
+
+// tag color tag setting the text color
+func tag(color string) string {
+	return "[" + color + "]"
+}
+
+// reverse the selected rows of the lists, used when colors are turned off and selection can't be shown with them
+func (ui *UI) reverseSelectedRows(screen tcell.Screen) {
+	if frontPage, _ := ui.pages.GetFrontPage(); frontPage != feedPage {
+		return
+	}
+	for _, list := range []*tview.List{ui.feedList, ui.entriesList} {
+		x, y, width, height := list.GetInnerRect()
+		offset, _ := list.GetOffset()
+		row := list.GetCurrentItem() - offset
+		if list.GetItemCount() == 0 || row < 0 || row >= height {
+			continue
+		}
+		for column := x; column < x+width; column++ {
+			mainc, combc, style, _ := screen.GetContent(column, y+row)
+			screen.SetContent(column, y+row, mainc, combc, style.Reverse(true))
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestBuiltInThemesUseKnownColors(t *testing.T) {
+	for name, theme := range builtInThemes {
+		assert.Nil(t, theme.check(name))
+	}
+	assert.Nil(t, monochromeTheme.check("monochrome"))
+}
+
+func TestConfigTheme(t *testing.T) {
+	defer restoreEnv("NO_COLOR")()
+	_ = os.Unsetenv("NO_COLOR")
+
+	theme, err := (&ConfigData{}).theme()
+	assert.Nil(t, err)
+	assert.Equal(t, builtInThemes["dark"], theme)
+
+	theme, err = (&ConfigData{Theme: "light"}).theme()
+	assert.Nil(t, err)
+	assert.Equal(t, builtInThemes["light"], theme)
+
+	config := &ConfigData{Theme: "mine", Themes: map[string]Theme{"mine": {Focus: "#ff8800", Unread: "orange"}}}
+	theme, err = config.theme()
+	assert.Nil(t, err)
+	assert.Equal(t, "#ff8800", theme.Focus)
+	assert.Equal(t, "orange", theme.Unread)
+	assert.Equal(t, builtInThemes["dark"].Border, theme.Border)
+
+	_, err = (&ConfigData{Theme: "solarized"}).theme()
+	assert.Equal(t, "error unknown theme solarized", err.Error())
+
+	config.Themes["mine"] = Theme{Border: "blurple"}
+	_, err = config.theme()
+	assert.Equal(t, "error unknown color blurple in theme mine", err.Error())
+}
+
+func TestNoColorTurnsOffColors(t *testing.T) {
+	defer restoreEnv("NO_COLOR")()
+	_ = os.Setenv("NO_COLOR", "1")
+
+	theme, err := (&ConfigData{Theme: "high-contrast"}).theme()
+	assert.Nil(t, err)
+	assert.Equal(t, monochromeTheme, theme)
+
+	data := createTestData(false)
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+
+	ui.pages.SetRect(0, 0, 150, 150)
+	ui.pages.Draw(simScreen)
+	ui.reverseSelectedRows(simScreen)
+
+	x, y, _, _ := ui.feedList.GetInnerRect()
+	_, _, style, _ := simScreen.GetContent(x, y)
+	_, _, attributes := style.Decompose()
+	assert.NotZero(t, attributes&tcell.AttrReverse)
+	_, _, style, _ = simScreen.GetContent(x, y+1)
+	_, _, attributes = style.Decompose()
+	assert.Zero(t, attributes&tcell.AttrReverse)
+
+	assert.True(t, strings.HasPrefix(ui.menuTextView.GetText(false), `["refresh"][default:default]`))
+	assert.Equal(t, tcell.ColorDefault, ui.feedList.GetBorderColor())
+}
+
+func TestParseConfigWithTheme(t *testing.T) {
+	defer restoreEnv("NO_COLOR")()
+	_ = os.Unsetenv("NO_COLOR")
+
+	_, err := parseConfig(strings.NewReader(`{"theme": "mine", "themes": {"mine": {"focus": "green"}}, "feeds": []}`))
+	assert.Nil(t, err)
+
+	_, err = parseConfig(strings.NewReader(`{"theme": "mine", "feeds": []}`))
+	assert.Equal(t, "error unknown theme mine", err.Error())
+}
+
+func TestLightThemeColorsInterface(t *testing.T) {
+	defer restoreEnv("NO_COLOR")()
+	_ = os.Unsetenv("NO_COLOR")
+
+	data := createTestData(false)
+	data.configData.Theme = "light"
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+	ui.feedList.SetCurrentItem(1)
+
+	assert.Equal(t, tcell.ColorBlue, ui.feedList.GetBorderColor())
+	ui.switchAppFocus(ui.entriesList.Box, ui.feedList.Box, ui.entriesList)
+	assert.Equal(t, tcell.ColorGray, ui.feedList.GetBorderColor())
+
+	entry := data.feedEntries(testURLOne)[0]
+	entry.isNew = true
+	assert.Equal(t, "[darkgreen]new[-] [::b]registry fake title one", ui.entryListText(entry))
+}
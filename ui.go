@@ -14,6 +14,13 @@ const quitPage = "quitPage"
 const refreshPage = "refreshPage"
 const errorPage = "errorPage"
 const openBrowserPage = "open"
+const messagePage = "messagePage"
+const searchPage = "searchPage"
+const linksPage = "linksPage"
+const feedFormPage = "feedFormPage"
+const deleteFeedPage = "deleteFeedPage"
+const listDateFormat = "2006-01-02"
+const headerDateFormat = "Mon, 2 Jan 2006 15:04 MST"
 const refreshMenuRegion = "refresh"
 const helpMenuRegion = "help"
 const quitMenuRegion = "quit"
@@ -30,6 +37,35 @@ type UI struct {
 	menuTextView    *tview.TextView
 	previousFocus   tview.Primitive
 	pages           *tview.Pages
+	loadingEntries  bool
+	populatingFeeds bool
+	sortMode        sortMode
+	entriesURL      string
+	searchQuery     string
+	filterList      *tview.List
+	filterQuery     string
+	keymap          *Keymap
+	vimMode         bool
+	vimLeader       string
+	vim             vimState
+	theme           Theme
+	entryLinks      []string
+	configFileName  string
+	// entries in the rows of the entries list, taken when the list is loaded so rows and entries stay in step
+	// while feeds are refreshed in the background
+	listed []Entry
+	// entries of the listed feed before they are filtered and the text of their rows the filter is matched against,
+	// worked out once when the feed is listed rather than on every key typed into the filter
+	unfiltered      []Entry
+	unfilteredTexts []string
+	// results of the search submitted and the version of the search index they were found in
+	searchResults []Entry
+	searchVersion int
+	// paths of the folders of the feed list that are collapsed, folders start expanded
+	collapsedFolders map[string]bool
+	// articles being fetched and articles fetched automatically in reader mode
+	fetchingArticles    map[entryRef]bool
+	autoFetchedArticles map[entryRef]bool
 }
 
 // CreateUI create and configure all ui elements for app start up
@@ -37,14 +73,51 @@ func CreateUI(app TermApplication, data *Data) *UI {
 	ui := &UI{}
 	ui.app = app
 	ui.data = data
+	ui.fetchingArticles = make(map[entryRef]bool)
+	ui.collapsedFolders = make(map[string]bool)
+	ui.autoFetchedArticles = make(map[entryRef]bool)
+
+	var bindings map[string][]string
+	config := &ConfigData{}
+	ui.vimLeader = defaultVimLeader
+	if data != nil {
+		config = data.configData
+		bindings = data.configData.Keys
+		ui.vimMode = data.configData.VimMode
+		if data.configData.VimLeader != "" {
+			leader, err := canonicalKeyName(data.configData.VimLeader)
+			if err != nil {
+				panic(err)
+			}
+			ui.vimLeader = leader
+		}
+	}
+	keymap, err := newKeymap(bindings)
+	if err != nil {
+		panic(err)
+	}
+	ui.keymap = keymap
+
+	theme, err := config.theme()
+	if err != nil {
+		panic(err)
+	}
+	ui.theme = theme
+	// primitives take their colors from tview's styles when they are created
+	tview.Styles = theme.styles()
+	if theme.reverseSelection {
+		ui.app.SetAfterDrawFunc(ui.reverseSelectedRows)
+	}
 
 	ui.feedList = tview.NewList().ShowSecondaryText(false)
 	ui.feedList.SetBorder(true).SetTitle("Feeds")
-	ui.feedList.SetBorderColor(tcell.ColorBlue)
+	ui.feedList.SetBorderColor(color(theme.Focus))
+	ui.feedList.SetSelectedTextColor(color(theme.SelectedText)).SetSelectedBackgroundColor(color(theme.SelectedBackground))
 	ui.feedList.AddItem("Fetching Feed Data", "", 0, nil)
 
 	ui.entriesList = tview.NewList().ShowSecondaryText(false)
 	ui.entriesList.SetBorder(true).SetTitle("Entries")
+	ui.entriesList.SetSelectedTextColor(color(theme.SelectedText)).SetSelectedBackgroundColor(color(theme.SelectedBackground))
 	ui.entriesList.AddItem("Fetching Feed Data", "", 0, nil)
 
 	ui.entryTextView = tview.NewTextView().
@@ -54,12 +127,17 @@ func CreateUI(app TermApplication, data *Data) *UI {
 
 	ui.entryTextView.SetBorder(true)
 	ui.entryTextView.SetWordWrap(true)
+	ui.entryTextView.SetDynamicColors(true)
 	ui.entryTextView.SetTitle("Description")
 	ui.entryTextView.SetText("Fetching Feed Data")
 
 	ui.menuTextView = tview.NewTextView()
 	ui.menuTextView.SetRegions(true).SetDynamicColors(true).SetBorder(false)
-	ui.menuTextView.SetText(`["` + refreshMenuRegion + `"][white:blue](r) Refresh [""][:black] ["` + helpMenuRegion + `"][white:blue](h) Help [""][:black] ["` + quitMenuRegion + `"][white:blue](q) Quit [""]`)
+	menuColors := tag(theme.MenuText + ":" + theme.MenuBackground)
+	gapColors := tag(":" + theme.Background)
+	ui.menuTextView.SetText(`["` + refreshMenuRegion + `"]` + menuColors + `(` + ui.menuKey(actionRefresh) + `) Refresh [""]` + gapColors + ` ["` +
+		helpMenuRegion + `"]` + menuColors + `(` + ui.menuKey(actionHelp) + `) Help [""]` + gapColors + ` ["` +
+		quitMenuRegion + `"]` + menuColors + `(` + ui.menuKey(actionQuit) + `) Quit [""]`)
 
 	ui.flex = tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(tview.NewFlex().
@@ -85,6 +163,13 @@ func CreateUI(app TermApplication, data *Data) *UI {
 
 // load
 func (ui *UI) loadAllFeedDataAndUpdateInterface() {
+	// list every feed straight away, each row is updated as its fetch finishes
+	ui.updateInterface()
+	ui.refreshAllFeeds()
+}
+
+// fetch every feed, rows are updated in place so the selected feed, entry and scroll position are kept
+func (ui *UI) refreshAllFeeds() {
 	defer func() {
 		if r := recover(); r != nil {
 			err := r.(error)
@@ -94,12 +179,10 @@ func (ui *UI) loadAllFeedDataAndUpdateInterface() {
 		}
 	}()
 
-	ui.data.safeFeedData.Clear()
-	err := ui.data.loadDataFromFeeds()
+	err := ui.data.loadDataFromFeeds(ui.updateFeedRow)
 	if err != nil {
 		panic(err)
 	}
-	ui.updateInterface()
 }
 
 // start ui run loop
@@ -116,92 +199,514 @@ func (ui *UI) updateInterface() {
 	ui.app.QueueUpdateDraw(ui.setupLists)
 }
 
+// Using QueueUpdateDraw to refresh the row of a single feed once its data has been fetched
+func (ui *UI) updateFeedRow(url string) {
+	ui.app.QueueUpdateDraw(func() {
+		// the feed has no row when its folder is collapsed or it is filtered out,
+		// the all feeds and folder rows still count it though
+		if i := ui.feedRowIndex(url); i >= 0 {
+			ui.feedList.SetItemText(i, ui.feedRowText(url), url)
+		}
+		ui.updateAllFeedsRow()
+		// if the user is looking at this feed load its entries as well
+		if ui.entriesURL == url || ui.entriesURL == allFeedsURL || ui.entriesURL == searchResultsURL || isFolderURL(ui.entriesURL) {
+			ui.reloadEntries(ui.entriesURL)
+		}
+	})
+}
+
+// update the unread counts on the all feeds row and the rows of folders
+func (ui *UI) updateAllFeedsRow() {
+	for i := 0; i < ui.feedList.GetItemCount(); i++ {
+		if _, url := ui.feedList.GetItemText(i); url == allFeedsURL || isFolderURL(url) {
+			ui.feedList.SetItemText(i, ui.feedRowText(url), url)
+		}
+	}
+}
+
+// reload the entries of a feed keeping the same entry selected and scrolled to the same place
+func (ui *UI) reloadEntries(url string) {
+	ui.keepSelectedEntry(func() {
+		ui.loadEntriesIntoList(url)
+	})
+}
+
+// run load, which lists entries again, keeping the same entry selected and scrolled to the same place,
+// rows are matched by the url of their entry
+func (ui *UI) keepSelectedEntry(load func()) {
+	selectedURL := ""
+	if ui.entriesList.GetItemCount() > 0 {
+		_, selectedURL = ui.entriesList.GetItemText(ui.entriesList.GetCurrentItem())
+	}
+	row, column := ui.entryTextView.GetScrollOffset()
+
+	load()
+
+	entries := ui.listedEntries()
+	sameEntry := false
+	for i, entry := range entries {
+		if entry.url == selectedURL {
+			ui.entriesList.SetCurrentItem(i)
+			sameEntry = true
+			break
+		}
+	}
+	if len(entries) > 0 {
+		ui.loadEntryTextView(ui.entriesList.GetCurrentItem())
+		if sameEntry {
+			ui.entryTextView.ScrollTo(row, column)
+		}
+	}
+}
+
+// text of the row of a feed in the feed list indented by the folders it is in, with the characters matched
+// by a filter highlighted
+func (ui *UI) feedRowText(url string) string {
+	return ui.feedRowIndent(url) + ui.highlightFilter(ui.feedList, ui.feedListText(url))
+}
+
+// name of feed to display in feed list, feeds still being fetched show their url, failed feeds their error,
+// a name given to the feed in the config replaces the feed's title
+func (ui *UI) feedListText(url string) string {
+	if url == allFeedsURL {
+		unread := 0
+		for _, feed := range ui.data.configData.enabledFeeds() {
+			unread += ui.data.safeFeedData.GetEntries(feed.URL).unreadCount()
+		}
+		if unread > 0 {
+			return fmt.Sprintf("All (%d)", unread)
+		}
+		return "All"
+	}
+	if isFolderURL(url) {
+		return ui.folderListText(url)
+	}
+
+	feedData := ui.data.safeFeedData.GetEntries(url)
+	if feedData.err != nil {
+		return tag(ui.theme.Error) + tview.Escape("Failed "+url+" - "+feedData.err.Error())
+	}
+	if feedData.name == "" {
+		return "Fetching " + url
+	}
+	if unread := feedData.unreadCount(); unread > 0 {
+		return fmt.Sprintf("%s (%d)", tview.Escape(ui.feedName(url)), unread)
+	}
+	return tview.Escape(ui.feedName(url))
+}
+
+// name of a feed without any unread count or error
+func (ui *UI) feedName(url string) string {
+	return ui.data.feedName(url)
+}
+
+// title of entry to display in entries list with the characters matched by a filter highlighted
+func (ui *UI) entryListText(entry Entry) string {
+	return ui.highlightFilter(ui.entriesList, ui.entryRowText(entry))
+}
+
+// title of entry shown in its row of the entries list, unread entries are shown in bold and new ones marked new,
+// entries listed together with other feeds, in all feeds, a folder or search results, show their feed's name and are prefixed with their date
+// when the config turns on dates
+func (ui *UI) entryRowText(entry Entry) string {
+	text := tview.Escape(entry.title)
+	if !entry.read {
+		text = tag(ui.theme.Unread+"::b") + text
+	}
+	if ui.entriesURL == allFeedsURL || ui.entriesURL == searchResultsURL || isFolderURL(ui.entriesURL) {
+		text = tag(ui.theme.Accent) + tview.Escape(ui.feedName(entry.feedURL)) + "[-:-:-] " + text
+	}
+	if entry.isNew {
+		text = tag(ui.theme.New) + "new[-] " + text
+	}
+	if ui.data.configData.ShowEntryDates {
+		date := strings.Repeat(" ", len(listDateFormat))
+		if !entry.date().IsZero() {
+			date = entry.date().Local().Format(listDateFormat)
+		}
+		text = tag(ui.theme.Muted) + date + "[-] " + text
+	}
+	return text
+}
+
+// header shown above the content of an entry with its author, dates, tags and link
+func entryHeader(entry Entry) string {
+	var stringBuilder strings.Builder
+	if entry.author != "" {
+		_, _ = fmt.Fprintf(&stringBuilder, "[::b]Author:[::-] %s\n", tview.Escape(entry.author))
+	}
+	if !entry.published.IsZero() {
+		_, _ = fmt.Fprintf(&stringBuilder, "[::b]Published:[::-] %s\n", entry.published.Local().Format(headerDateFormat))
+	}
+	if !entry.updated.IsZero() && !entry.updated.Equal(entry.published) {
+		_, _ = fmt.Fprintf(&stringBuilder, "[::b]Updated:[::-] %s\n", entry.updated.Local().Format(headerDateFormat))
+	}
+	if len(entry.categories) > 0 {
+		_, _ = fmt.Fprintf(&stringBuilder, "[::b]Tags:[::-] %s\n", tview.Escape(strings.Join(entry.categories, ", ")))
+	}
+	if entry.url != "" {
+		_, _ = fmt.Fprintf(&stringBuilder, "[::b]Link:[::-] %s\n", tview.Escape(entry.url))
+	}
+	return stringBuilder.String()
+}
+
 // load data into list and setup functions to handle user navigating list
 func (ui *UI) setupLists() {
-	ui.feedList.Clear()
-	// add items to feed list
-	for _, feed := range ui.data.configData.Feeds {
-		feedData := ui.data.safeFeedData.GetEntries(feed.URL)
-		ui.feedList.AddItem(feedData.name, feed.URL, 0, func() {
-			// handle user selecting item by moving focus to entry list
-			ui.switchAppFocus(ui.entriesList.Box, ui.feedList.Box, ui.entriesList)
-		})
-	}
+	ui.populateFeedList()
 
 	// handle user changing selected feed item by loading entries list
 	ui.feedList.SetChangedFunc(func(i int, feedName string, url string, shortcut rune) {
-		ui.loadEntriesIntoList(url)
+		if !ui.populatingFeeds {
+			ui.loadEntriesIntoList(url)
+		}
 	})
 
 	// handle user changing selected item of entries list by loading entry text view
 	ui.entriesList.SetChangedFunc(func(i int, entryName string, secondaryText string, shortcut rune) {
 		ui.loadEntryTextView(i)
+		// only count entries as read when the user is moving through them, not when the list is loaded
+		if ui.app.GetFocus() == ui.entriesList && !ui.loadingEntries {
+			ui.setEntryRead(i, true)
+		}
 	})
 
-	// when user hits escape in entries list, move focus back to feed list
+	// when user hits escape in the description, move focus back to entries list
+	ui.entryTextView.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			ui.switchAppFocus(ui.entriesList.Box, ui.entryTextView.Box, ui.entriesList)
+		}
+	})
+
+	// when user hits escape in entries list, move focus back to feed list and leave any search results
 	ui.entriesList.SetDoneFunc(func() {
 		ui.switchAppFocus(ui.feedList.Box, ui.entriesList.Box, ui.feedList)
+		if ui.entriesURL == searchResultsURL {
+			ui.loadEntriesIntoList(ui.getSelectedFeedURL())
+			ui.loadEntryTextView(0)
+		}
 	})
 
 	// load initial state of interface
 	ui.loadEntriesIntoList(ui.getSelectedFeedURL())
 	//make sure there's at least one entry in selected
-	if len(ui.data.safeFeedData.GetEntries(ui.getSelectedFeedURL()).entries) > 0 {
+	if len(ui.listedEntries()) > 0 {
 		ui.loadEntryTextView(0)
 	}
 
 }
 
+// add a row to the feed list for every feed the filter matches, starting with the entries of every feed merged
+// together, feeds are listed inside the folders of their category, the selected feed stays selected if it is
+// still listed and otherwise the folder it is collapsed into is selected
+func (ui *UI) populateFeedList() {
+	selectedURL := ui.getSelectedFeedURL()
+	// rebuilding the rows moves the selection, which would list the entries again and lose the selected entry
+	ui.populatingFeeds = true
+	ui.feedList.Clear()
+	// handle user selecting item by moving focus to entry list, the entry shown is now being read
+	selectFeed := func() {
+		ui.switchAppFocus(ui.entriesList.Box, ui.feedList.Box, ui.entriesList)
+		ui.setEntryRead(ui.entriesList.GetCurrentItem(), true)
+	}
+
+	if ui.matchesFilter(ui.feedList, ui.feedListText(allFeedsURL)) {
+		ui.feedList.AddItem(ui.feedRowText(allFeedsURL), allFeedsURL, 0, selectFeed)
+	}
+	for _, url := range ui.feedTreeURLs(ui.data.configData.feedTree()) {
+		ui.feedList.AddItem(ui.feedRowText(url), url, 0, selectFeed)
+	}
+
+	i := ui.feedRowIndex(selectedURL)
+	for folder := ui.data.configData.parentFolder(selectedURL); i < 0 && folder != ""; folder = ui.data.configData.parentFolder(folderURL(folder)) {
+		i = ui.feedRowIndex(folderURL(folder))
+	}
+	if i > 0 {
+		ui.feedList.SetCurrentItem(i)
+	}
+	ui.populatingFeeds = false
+	ui.feedList.SetTitle("Feeds" + ui.filterTitle(ui.feedList))
+
+	// list the entries of the row selected instead, keeping the selected entry if it is one of them
+	if url := ui.getSelectedFeedURL(); ui.entriesURL != searchResultsURL && url != ui.entriesURL {
+		ui.reloadEntries(url)
+	}
+}
+
+// switch focus between the lists and modals, a list stops being filtered once focus moves away from it
 func (ui *UI) switchAppFocus(newBox *tview.Box, oldBox *tview.Box, newFocus tview.Primitive) {
-	oldBox.SetBorderColor(tcell.ColorWhite)
-	newBox.SetBorderColor(tcell.ColorBlue)
+	ui.stopFilter()
+	oldBox.SetBorderColor(color(ui.theme.Border))
+	newBox.SetBorderColor(color(ui.theme.Focus))
 	ui.app.SetFocus(newFocus)
 }
 
 // Looks up the text of the corresponding entry and sets it on the text view
 func (ui *UI) loadEntryTextView(i int) {
 	ui.entryTextView.Clear()
-	feedData := ui.data.safeFeedData.GetEntries(ui.getSelectedFeedURL())
-	if feedData.entries != nil {
-		ui.entryTextView.SetText(feedData.entries[i].content)
+	ui.entryTextView.ScrollToBeginning()
+	ui.entryLinks = nil
+	entries := ui.listedEntries()
+	if i < len(entries) {
+		entry := entries[i]
+		content := entry.content
+		if entry.fullContent != "" {
+			content = entry.fullContent
+		}
+		content, ui.entryLinks = renderContent(content, entry.url)
+		ui.entryTextView.SetText(entryHeader(entry) + "\n" + content)
+		ui.autoFetchArticle(entry)
+	}
+}
+
+// entries listed for a row of the feed list in the current sort mode, the all feeds row and folders are always
+// sorted by date
+func (ui *UI) entriesFor(url string) []Entry {
+	if url == searchResultsURL {
+		return ui.cachedSearchResults()
+	}
+	if url == allFeedsURL || isFolderURL(url) {
+		mode := ui.sortMode
+		if mode == publisherOrder {
+			mode = newestFirst
+		}
+		if url == allFeedsURL {
+			return sortEntries(ui.data.allEntries(), mode)
+		}
+		return sortEntries(ui.data.folderEntries(folderPath(url)), mode)
 	}
+	return sortEntries(ui.data.feedEntries(url), ui.sortMode)
+}
+
+// entries in the rows of the entries list
+func (ui *UI) listedEntries() []Entry {
+	return ui.listed
+}
+
+// entries of the listed feed that match the filter
+func (ui *UI) filterEntries() []Entry {
+	if ui.filterList != ui.entriesList || ui.filterQuery == "" {
+		return ui.unfiltered
+	}
+	var listed []Entry
+	for i, entry := range ui.unfiltered {
+		if _, ok := fuzzyMatch(ui.unfilteredTexts[i], ui.filterQuery); ok {
+			listed = append(listed, entry)
+		}
+	}
+	return listed
+}
+
+// bring the entries of the entries list up to date with what has been read and the articles fetched
+func (ui *UI) refreshListedEntries() {
+	ui.data.refreshEntries(ui.unfiltered)
+	ui.data.refreshEntries(ui.listed)
+}
+
+// set read state of entry i of the selected feed and update its row and the unread count of its feed
+func (ui *UI) setEntryRead(i int, read bool) {
+	entries := ui.listedEntries()
+	if i >= len(entries) || entries[i].read == read {
+		return
+	}
+
+	err := ui.data.setEntriesRead(entries[i].feedURL, []string{entries[i].guid}, read)
+	if err != nil {
+		ui.createMessagePage(err.Error())
+	}
+	ui.updateReadState()
+}
+
+// flip the selected entry between read and unread
+func (ui *UI) toggleSelectedEntryRead() {
+	entries := ui.listedEntries()
+	i := ui.entriesList.GetCurrentItem()
+	if i < len(entries) {
+		ui.setEntryRead(i, !entries[i].read)
+	}
+}
+
+// mark every entry in the entries list as read, on the all feeds row that is every entry of every feed
+func (ui *UI) markSelectedFeedRead() {
+	err := ui.data.markEntriesRead(ui.listedEntries())
+	if err != nil {
+		ui.createMessagePage(err.Error())
+	}
+	ui.updateReadState()
+}
+
+// redraw entry rows and feed rows after the read state of entries has changed
+func (ui *UI) updateReadState() {
+	ui.refreshListedEntries()
+	feedData := ui.data.safeFeedData.GetEntries(ui.entriesURL)
+	if feedData.err == nil || len(feedData.entries) > 0 {
+		for i, entry := range ui.listedEntries() {
+			if i < ui.entriesList.GetItemCount() {
+				ui.entriesList.SetItemText(i, ui.entryListText(entry), entry.url)
+			}
+		}
+	}
+	for i := 0; i < ui.feedList.GetItemCount(); i++ {
+		_, url := ui.feedList.GetItemText(i)
+		ui.feedList.SetItemText(i, ui.feedRowText(url), url)
+	}
+}
+
+// switch to the next sort mode and reload the entries list in that order
+func (ui *UI) cycleSortMode() {
+	ui.sortMode = ui.sortMode.next()
+	ui.reloadEntries(ui.entriesURL)
+}
+
+// title of the entries list describing what it is showing
+func (ui *UI) entriesTitle() string {
+	if ui.entriesURL == searchResultsURL {
+		return fmt.Sprintf("Search: %s (%d)", tview.Escape(ui.searchQuery), ui.entriesList.GetItemCount()) + ui.filterTitle(ui.entriesList)
+	}
+	if ui.sortMode == publisherOrder {
+		return "Entries" + ui.filterTitle(ui.entriesList)
+	}
+	return "Entries (" + ui.sortMode.String() + ")" + ui.filterTitle(ui.entriesList)
+}
+
+// results of the search submitted, the search is only run again once the index has changed,
+// until then the results are brought up to date with what has been read
+func (ui *UI) cachedSearchResults() []Entry {
+	if version := ui.data.safeFeedData.index.Version(); version != ui.searchVersion {
+		ui.searchResults = ui.data.searchEntries(ui.searchQuery)
+		ui.searchVersion = version
+	}
+	results := make([]Entry, len(ui.searchResults))
+	copy(results, ui.searchResults)
+	ui.data.refreshEntries(results)
+	return results
+}
+
+// show the entries matching query in the entries list and move focus there
+func (ui *UI) showSearchResults(query string) {
+	ui.searchQuery = query
+	ui.searchVersion = ui.data.safeFeedData.index.Version()
+	ui.searchResults = ui.data.searchEntries(query)
+	ui.loadEntriesIntoList(searchResultsURL)
+	ui.switchAppFocus(ui.entriesList.Box, ui.feedList.Box, ui.entriesList)
+	if ui.entriesList.GetItemCount() == 0 {
+		ui.entryTextView.SetText("No entries match " + tview.Escape(query))
+		return
+	}
+	ui.loadEntryTextView(0)
+	ui.setEntryRead(0, true)
+}
+
+// index of the row in feed list showing the feed with url, -1 if there isn't one
+func (ui *UI) feedRowIndex(url string) int {
+	for i := 0; i < ui.feedList.GetItemCount(); i++ {
+		if _, rowURL := ui.feedList.GetItemText(i); rowURL == url {
+			return i
+		}
+	}
+	return -1
 }
 
 // Urls of feeds are stored as secondary text on list items, uses that to look up selected feed
 func (ui *UI) getSelectedFeedURL() string {
+	if ui.feedList.GetItemCount() == 0 {
+		return ""
+	}
 	_, url := ui.feedList.GetItemText(ui.feedList.GetCurrentItem())
 	return url
 }
 
 // Send the entries for the selected feed into the entry list
 func (ui *UI) loadEntriesIntoList(url string) {
-	ui.entriesList.Clear()
+	ui.loadingEntries = true
+	defer func() { ui.loadingEntries = false }()
+
+	// the feed list's changed func runs before its selection moves, so remember which feed is listed
+	ui.entriesURL = url
+	ui.unfiltered, ui.unfilteredTexts = nil, nil
 	feedData := ui.data.safeFeedData.GetEntries(url)
-	for _, entry := range feedData.entries {
-		ui.entriesList.AddItem(entry.title, entry.url, 0, func() {
-			// when an item in the entry list is selected, open the link in the browser
-			_, url = ui.entriesList.GetItemText(ui.entriesList.GetCurrentItem())
-			// if on windows escape &
-			if runtime.GOOS == "windows" {
-				strings.ReplaceAll(url, "&", "^&")
+	if feedData.err != nil && len(feedData.entries) == 0 {
+		ui.entriesList.Clear()
+		ui.listed = nil
+		ui.entriesList.AddItem("Failed to load feed", "", 0, nil)
+		ui.entryTextView.SetText(tview.Escape(feedData.err.Error()))
+		ui.entriesList.SetTitle(ui.entriesTitle())
+		return
+	}
+
+	ui.unfiltered = ui.entriesFor(url)
+	ui.unfilteredTexts = make([]string, len(ui.unfiltered))
+	for i, entry := range ui.unfiltered {
+		ui.unfilteredTexts[i] = visibleText(ui.entryRowText(entry))
+	}
+	ui.listFilteredEntries()
+}
+
+// fill the entries list with the entries of the listed feed that match the filter
+func (ui *UI) listFilteredEntries() {
+	ui.loadingEntries = true
+	defer func() { ui.loadingEntries = false }()
+
+	ui.entriesList.Clear()
+	ui.listed = ui.filterEntries()
+	for _, entry := range ui.listed {
+		// when an item in the entry list is selected, open the link in the browser
+		ui.entriesList.AddItem(ui.entryListText(entry), entry.url, 0, ui.openSelectedEntry)
+	}
+	ui.entriesList.SetTitle(ui.entriesTitle())
+}
+
+// ask the user if they want to open the selected entry in their browser
+func (ui *UI) openSelectedEntry() {
+	if ui.entriesList.GetItemCount() == 0 {
+		return
+	}
+	_, entryURL := ui.entriesList.GetItemText(ui.entriesList.GetCurrentItem())
+	if entryURL == "" {
+		return
+	}
+	// if on windows escape &
+	if runtime.GOOS == "windows" {
+		strings.ReplaceAll(entryURL, "&", "^&")
+	}
+	//use gox library to make platform specific call to open url in browser
+
+	openBrowserModal := ui.createOverlayModal(openBrowserPage, "Open entry in browser?", []string{"Yes", "No"},
+		func(buttonIndex int, buttonLabel string) {
+			if buttonLabel == "Yes" {
+				err := ui.browserLauncher.OpenDefault(entryURL)
+				if err != nil {
+					panic(err)
+				}
+				ui.setEntryRead(ui.entriesList.GetCurrentItem(), true)
 			}
-			//use gox library to make platform specific call to open url in browser
-
-			openBrowserModal := ui.createOverlayModal(openBrowserPage, "Open entry in browser?", []string{"Yes", "No"},
-				func(buttonIndex int, buttonLabel string) {
-					if buttonLabel == "Yes" {
-						err := ui.browserLauncher.OpenDefault(url)
-						if err != nil {
-							panic(err)
-						}
-					}
-					ui.pages.SwitchToPage(feedPage)
-					ui.pages.RemovePage(openBrowserPage)
-					ui.switchAppFocus(ui.entriesList.Box, ui.entryTextView.Box, ui.entriesList)
-					ui.app.SetFocus(ui.entriesList)
-				})
-			ui.switchAppFocus(ui.entryTextView.Box, ui.entriesList.Box, openBrowserModal)
+			ui.pages.SwitchToPage(feedPage)
+			ui.pages.RemovePage(openBrowserPage)
+			ui.switchAppFocus(ui.entriesList.Box, ui.entryTextView.Box, ui.entriesList)
+			ui.app.SetFocus(ui.entriesList)
 		})
+	ui.switchAppFocus(ui.entryTextView.Box, ui.entriesList.Box, openBrowserModal)
+}
+
+// first key bound to action shown in the menu
+func (ui *UI) menuKey(action string) string {
+	keys := ui.keymap.keysFor(action)
+	if len(keys) == 0 {
+		return "-"
+	}
+	return ui.shortcutName(keys[0])
+}
+
+// move the selection of list by offset rows, stopping at the first or last row
+func moveSelection(list *tview.List, offset int) {
+	i := list.GetCurrentItem() + offset
+	if i < 0 {
+		i = 0
+	}
+	if i >= list.GetItemCount() {
+		i = list.GetItemCount() - 1
+	}
+	if i != list.GetCurrentItem() {
+		list.SetCurrentItem(i)
 	}
 }
 
@@ -211,19 +716,102 @@ func (ui *UI) setInputCaptureHandler() {
 }
 
 func (ui *UI) handleKeyboardPressEvents(event *tcell.EventKey) *tcell.EventKey {
-	switch event.Key() {
-	case tcell.KeyRune:
-		switch event.Rune() {
-		case 'q':
-			ui.createQuitPage()
-			ui.menuTextView.Highlight(quitMenuRegion)
-		case 'h':
-			ui.createHelpPage()
-			ui.menuTextView.Highlight(helpMenuRegion)
-		case 'r':
-			ui.createRefreshPage()
-			ui.menuTextView.Highlight(refreshMenuRegion)
+	// keys typed into a prompt, a form, the link chooser or a modal in front of the feeds aren't shortcuts
+	if _, ok := ui.app.GetFocus().(*tview.InputField); ok {
+		return event
+	}
+	if frontPage, _ := ui.pages.GetFrontPage(); frontPage != feedPage {
+		return event
+	}
+	// while filtering a list typed keys narrow it
+	if ui.filterList != nil && ui.handleFilterKey(event) {
+		return nil
+	}
+	if ui.vimMode {
+		return ui.handleVimKey(event)
+	}
+	return ui.runShortcut(event)
+}
+
+// run the action bound to the key of event
+func (ui *UI) runShortcut(event *tcell.EventKey) *tcell.EventKey {
+	switch ui.keymap.action(event) {
+	case actionQuit:
+		ui.createQuitPage()
+		ui.menuTextView.Highlight(quitMenuRegion)
+	case actionHelp:
+		ui.createHelpPage()
+		ui.menuTextView.Highlight(helpMenuRegion)
+	case actionRefresh:
+		ui.createRefreshPage()
+		ui.menuTextView.Highlight(refreshMenuRegion)
+	case actionToggleRead:
+		ui.toggleSelectedEntryRead()
+	case actionMarkAllRead:
+		ui.markSelectedFeedRead()
+	case actionSort:
+		ui.cycleSortMode()
+	case actionSearch:
+		ui.createSearchPage()
+		return nil
+	case actionFilter:
+		ui.startFilter()
+		return nil
+	case actionNextFeed:
+		moveSelection(ui.feedList, 1)
+		return nil
+	case actionPreviousFeed:
+		moveSelection(ui.feedList, -1)
+		return nil
+	case actionNextEntry:
+		moveSelection(ui.entriesList, 1)
+		return nil
+	case actionPreviousEntry:
+		moveSelection(ui.entriesList, -1)
+		return nil
+	case actionOpen:
+		if ui.app.GetFocus() == ui.entriesList {
+			ui.openSelectedEntry()
 		}
+		return nil
+	case actionLinks:
+		ui.createLinksPage()
+		return nil
+	case actionReader:
+		ui.readSelectedEntry()
+		return nil
+	case actionAddFeed:
+		ui.createAddFeedPage()
+		return nil
+	case actionRenameFeed:
+		ui.createRenameFeedPage()
+		return nil
+	case actionDeleteFeed:
+		ui.createDeleteFeedPage()
+		return nil
+	// folder keys are passed on when the feed list doesn't have a folder to collapse or expand
+	case actionCollapseFolder:
+		if ui.app.GetFocus() == ui.feedList && ui.collapseSelectedFolder() {
+			return nil
+		}
+	case actionExpandFolder:
+		if ui.app.GetFocus() == ui.feedList && ui.expandSelectedFolder() {
+			return nil
+		}
+	// navigation keys are passed on to the focused list as the keys it already handles
+	case actionUp:
+		return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+	case actionDown:
+		return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+	case actionSelect:
+		return tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	case actionBack:
+		return tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone)
+	}
+	// number keys nothing is bound to open the links of the entry shown
+	if number, ok := linkNumber(event); ok && ui.showingEntry() {
+		ui.openEntryLink(number - 1)
+		return nil
 	}
 	return event
 }
@@ -242,16 +830,49 @@ func (ui *UI) createErrorPage(errorMessage string) *tview.Modal {
 	return errorBox
 }
 
+// create modal box displaying a message that doesn't need the app to quit
+func (ui *UI) createMessagePage(message string) *tview.Modal {
+	ui.previousFocus = ui.app.GetFocus()
+
+	messageBox := ui.createOverlayModal(messagePage, message, []string{"Okay"},
+		func(buttonIndex int, buttonLabel string) {
+			ui.pages.SwitchToPage(feedPage)
+			ui.pages.RemovePage(messagePage)
+			ui.app.SetFocus(ui.previousFocus)
+		})
+	ui.app.SetFocus(messageBox)
+
+	return messageBox
+}
+
 // create the modal box describing application's functions, embed in a page and display
 func (ui *UI) createHelpPage() {
 	ui.previousFocus = ui.app.GetFocus()
 
 	var stringBuilder strings.Builder
-	_, _ = fmt.Fprint(&stringBuilder, "\nUse Arrow keys to navigate list items\n")
-	_, _ = fmt.Fprint(&stringBuilder, "\nUse Enter and Esc to move between feed and entries lists\n")
-	_, _ = fmt.Fprint(&stringBuilder, "\nHit Enter on an entry to open it in your default browser\n")
-	_, _ = fmt.Fprint(&stringBuilder, "\nFeeds config are loaded from feeds.json\n")
-	_, _ = fmt.Fprint(&stringBuilder, "\nCtrl-C or q to exit\n")
+	if ui.vimMode {
+		for _, help := range vimHelp {
+			_, _ = fmt.Fprintf(&stringBuilder, "\n%s to %s\n", help[0], help[1])
+		}
+	}
+	for _, action := range keyActions {
+		var keys []string
+		for _, key := range ui.keymap.keysFor(action.name) {
+			keys = append(keys, ui.shortcutName(key))
+		}
+		if len(keys) > 0 {
+			_, _ = fmt.Fprintf(&stringBuilder, "\n%s to %s\n", strings.Join(keys, ", "), action.description)
+		}
+	}
+	_, _ = fmt.Fprintf(&stringBuilder, "\n%s-9 to open that numbered link of the entry in your browser, links from 10 on are "+
+		"opened by typing their number in the link chooser\n", ui.shortcutName("1"))
+	_, _ = fmt.Fprint(&stringBuilder, "\nFeeds and key bindings are loaded from feeds.json\n")
+	// Ctrl-C still quits when nothing is bound to quit
+	quitKey := "Ctrl-C"
+	if keys := ui.keymap.keysFor(actionQuit); len(keys) > 0 {
+		quitKey = ui.shortcutName(keys[0])
+	}
+	_, _ = fmt.Fprintf(&stringBuilder, "\n%s to exit\n", quitKey)
 
 	helpBox := ui.createOverlayModal(helpPage, stringBuilder.String(), []string{"Done"},
 		func(buttonIndex int, buttonLabel string) {
@@ -291,8 +912,7 @@ func (ui *UI) createRefreshPage() {
 	refreshBox := ui.createOverlayModal(refreshPage, "Do you want to refresh feed data?", []string{"Yes", "No"},
 		func(buttonIndex int, buttonLabel string) {
 			if buttonLabel == "Yes" {
-				ui.setUITextToFetchingData()
-				go ui.loadAllFeedDataAndUpdateInterface()
+				go ui.refreshAllFeeds()
 			}
 			ui.pages.SwitchToPage(feedPage)
 			ui.pages.RemovePage(refreshPage)
@@ -302,18 +922,40 @@ func (ui *UI) createRefreshPage() {
 	ui.app.SetFocus(refreshBox)
 }
 
-func (ui *UI) setUITextToFetchingData() {
-	ui.feedList.Clear()
-	ui.feedList.AddItem("Fetching Feed Data", "", 0, nil)
-	ui.entriesList.Clear()
-	ui.entriesList.AddItem("Fetching Feed Data", "", 0, nil)
-	ui.entryTextView.Clear()
-	ui.entryTextView.SetText("Fetching Feed Data")
+// create prompt asking for words to search entries for
+func (ui *UI) createSearchPage() *tview.InputField {
+	ui.previousFocus = ui.app.GetFocus()
+
+	searchField := tview.NewInputField().SetLabel("Search: ").SetText(ui.searchQuery)
+	searchField.SetBorder(true).SetTitle("Search Entries")
+	searchField.SetDoneFunc(func(key tcell.Key) {
+		ui.pages.SwitchToPage(feedPage)
+		ui.pages.RemovePage(searchPage)
+		ui.app.SetFocus(ui.previousFocus)
+		if key == tcell.KeyEnter && strings.TrimSpace(searchField.GetText()) != "" {
+			ui.showSearchResults(searchField.GetText())
+		}
+	})
 
+	// center the prompt over the feed page
+	prompt := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(searchField, 3, 0, true).
+			AddItem(nil, 0, 1, false), 60, 0, true).
+		AddItem(nil, 0, 1, false)
+	ui.pages.AddPage(searchPage, prompt, true, true)
+	ui.app.SetFocus(searchField)
+
+	return searchField
 }
 
 func (ui *UI) createOverlayModal(pageName, modalText string, buttons []string, buttonPressedHandler func(buttonIndex int, buttonLabel string)) *tview.Modal {
 	modalBox := tview.NewModal()
+	modalBox.SetBackgroundColor(color(ui.theme.ModalBackground)).SetTextColor(color(ui.theme.ModalText))
+	modalBox.SetButtonBackgroundColor(color(ui.theme.MenuBackground)).SetButtonTextColor(color(ui.theme.MenuText))
+	modalBox.SetBorderColor(color(ui.theme.Border))
 	modalBox.SetText(modalText)
 	modalBox.AddButtons(buttons)
 	modalBox.SetDoneFunc(buttonPressedHandler)
@@ -4,7 +4,9 @@ import (
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
+	"time"
 )
 
 const testURLOne = "theregistry.com"
@@ -82,11 +84,14 @@ func TestFeedsListContainsFeedNameAfterLoadingData(t *testing.T) {
 		f()
 	}
 
-	listItemOne, _ := ui.feedList.GetItemText(0)
-	assert.Equal(t, data.safeFeedData.GetEntries(testURLOne).name, listItemOne)
+	allFeeds, _ := ui.feedList.GetItemText(0)
+	assert.Equal(t, "All (4)", allFeeds)
 
-	listItemTwo, _ := ui.feedList.GetItemText(1)
-	assert.Equal(t, data.safeFeedData.GetEntries(testURLTwo).name, listItemTwo)
+	listItemOne, _ := ui.feedList.GetItemText(1)
+	assert.Equal(t, data.safeFeedData.GetEntries(testURLOne).name+" (2)", listItemOne)
+
+	listItemTwo, _ := ui.feedList.GetItemText(2)
+	assert.Equal(t, data.safeFeedData.GetEntries(testURLTwo).name+" (2)", listItemTwo)
 }
 
 func TestSwitchUIFocus(t *testing.T) {
@@ -116,19 +121,19 @@ func TestLoadEntryTextView(t *testing.T) {
 		f()
 	}
 
-	assert.Equal(t, data.safeFeedData.GetEntries(testURLOne).entries[0].content,
-		ui.entryTextView.GetText(true))
+	assert.Contains(t, ui.entryTextView.GetText(true),
+		data.safeFeedData.GetEntries(testURLOne).entries[0].content)
 
 	ui.loadEntryTextView(1)
 
-	assert.Equal(t, data.safeFeedData.GetEntries(testURLOne).entries[1].content,
-		ui.entryTextView.GetText(true))
+	assert.Contains(t, ui.entryTextView.GetText(true),
+		data.safeFeedData.GetEntries(testURLOne).entries[1].content)
 
-	ui.feedList.SetCurrentItem(1)
+	ui.feedList.SetCurrentItem(2)
 	ui.loadEntryTextView(0)
 
-	assert.Equal(t, data.safeFeedData.GetEntries(testURLTwo).entries[0].content,
-		ui.entryTextView.GetText(true))
+	assert.Contains(t, ui.entryTextView.GetText(true),
+		data.safeFeedData.GetEntries(testURLTwo).entries[0].content)
 }
 
 func TestLoadEntriesIntoList(t *testing.T) {
@@ -143,10 +148,10 @@ func TestLoadEntriesIntoList(t *testing.T) {
 	}
 
 	firstItemText, _ := ui.entriesList.GetItemText(ui.entriesList.GetCurrentItem())
-	assert.Equal(t, data.safeFeedData.GetEntries(testURLOne).entries[0].title, firstItemText)
+	assert.Equal(t, ui.entryListText(data.safeFeedData.GetEntries(testURLOne).entries[0]), firstItemText)
 
 	secondItemText, _ := ui.entriesList.GetItemText(1)
-	assert.Equal(t, data.safeFeedData.GetEntries(testURLOne).entries[1].title, secondItemText)
+	assert.Equal(t, ui.entryListText(data.safeFeedData.GetEntries(testURLOne).entries[1]), secondItemText)
 }
 
 func TestBrowserLauncherCausesPanic(t *testing.T) {
@@ -265,7 +270,7 @@ func TestHandleKeyboardPressHelpEvents(t *testing.T) {
 	simScreen.Show()
 
 	screenContentsAsString := getScreenContents(simScreen)
-	assert.Contains(t, screenContentsAsString, "Use Arrow keys to navigate list items")
+	assert.Contains(t, screenContentsAsString, "Up to move up the focused list")
 
 	keyEvent = tcell.NewEventKey(tcell.KeyEnter, rune(0), 0)
 	helpModal.InputHandler()(keyEvent, nil)
@@ -276,6 +281,28 @@ func TestHandleKeyboardPressHelpEvents(t *testing.T) {
 	assert.Equal(t, ui.feedList, ui.app.GetFocus())
 }
 
+func TestShortcutsDoNothingBehindModals(t *testing.T) {
+	data := createTestData(false)
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+
+	ui.setInputCaptureHandler()
+	capture := ui.app.GetInputCapture()
+	capture(tcell.NewEventKey(tcell.KeyRune, 'h', 0))
+
+	for _, key := range []rune{'d', 'a', 'q'} {
+		event := tcell.NewEventKey(tcell.KeyRune, key, 0)
+		assert.Equal(t, event, capture(event))
+	}
+
+	frontPage, _ := ui.pages.GetFrontPage()
+	assert.Equal(t, helpPage, frontPage)
+	assert.False(t, ui.pages.HasPage(deleteFeedPage))
+	assert.False(t, ui.pages.HasPage(quitPage))
+	assert.Equal(t, 2, data.safeFeedData.GetEntries(testURLOne).unreadCount())
+	assert.Equal(t, 2, data.safeFeedData.GetEntries(testURLTwo).unreadCount())
+}
+
 func TestHandleKeyboardPressRefreshEvents(t *testing.T) {
 	data := createTestData(false)
 	simScreen, ui := setupWithSimScreen(data)
@@ -310,8 +337,48 @@ func TestHandleKeyboardPressRefreshEvents(t *testing.T) {
 	currentFrontPage, _ = ui.pages.GetFrontPage()
 	assert.Equal(t, feedPage, currentFrontPage)
 
-	text, _ := ui.feedList.GetItemText(0)
-	assert.Equal(t, "Fetching Feed Data", text)
+	text, _ := ui.feedList.GetItemText(1)
+	assert.Equal(t, "registry (2)", text)
+	assert.Equal(t, 4, ui.entriesList.GetItemCount())
+}
+
+func TestRefreshAllFeedsUpdatesRowsInPlace(t *testing.T) {
+	data := createTestData(false)
+	app := CreateStubbedApp(true)
+	ui := CreateUI(app, data)
+	ui.setupLists()
+
+	ui.feedList.SetCurrentItem(2)
+	ui.entriesList.SetCurrentItem(1)
+
+	ui.refreshAllFeeds()
+	for _, f := range ui.app.(*StubbedApp).QueuedUpdateDraws() {
+		f()
+	}
+
+	assert.Equal(t, 2, ui.feedList.GetCurrentItem())
+	feedTitle, _ := ui.feedList.GetItemText(2)
+	assert.Equal(t, "Test Feed Title From Parser (2)", feedTitle)
+
+	entryTitle, _ := ui.entriesList.GetItemText(0)
+	assert.Equal(t, "[green]new[-] [::b]Test Entry Title One", entryTitle)
+	assert.Equal(t, 0, ui.entriesList.GetCurrentItem())
+}
+
+func TestReloadEntriesKeepsScrollPositionOfSameEntry(t *testing.T) {
+	data := createTestData(false)
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+
+	ui.entryTextView.ScrollTo(3, 0)
+	ui.reloadEntries(testURLOne)
+
+	row, _ := ui.entryTextView.GetScrollOffset()
+	assert.Equal(t, 3, row)
+
+	ui.entriesList.SetCurrentItem(1)
+	row, _ = ui.entryTextView.GetScrollOffset()
+	assert.Equal(t, 0, row)
 }
 
 func TestUserNavigatesLists(t *testing.T) {
@@ -319,15 +386,15 @@ func TestUserNavigatesLists(t *testing.T) {
 	simScreen, ui := setupWithSimScreen(data)
 	defer simScreen.Fini()
 
-	feedName, _ := ui.feedList.GetItemText(0)
-	assert.Equal(t, data.safeFeedData.GetEntries(testURLOne).name, feedName)
+	feedName, _ := ui.feedList.GetItemText(1)
+	assert.Equal(t, data.safeFeedData.GetEntries(testURLOne).name+" (2)", feedName)
 
-	ui.feedList.SetCurrentItem(1)
+	ui.feedList.SetCurrentItem(2)
 	entryName, _ := ui.entriesList.GetItemText(ui.entriesList.GetCurrentItem())
-	assert.Equal(t, data.safeFeedData.GetEntries(testURLTwo).entries[0].title, entryName)
+	assert.Equal(t, ui.entryListText(data.safeFeedData.GetEntries(testURLTwo).entries[0]), entryName)
 
 	ui.entriesList.SetCurrentItem(1)
-	assert.Equal(t, data.safeFeedData.GetEntries(testURLTwo).entries[1].content, ui.entryTextView.GetText(true))
+	assert.Contains(t, ui.entryTextView.GetText(true), data.safeFeedData.GetEntries(testURLTwo).entries[1].content)
 }
 
 func TestUserSelectingItemInFeedListAndLeavingEntriesList(t *testing.T) {
@@ -408,12 +475,105 @@ func TestLoadAllFeedDataAndUpdateInterface(t *testing.T) {
 		f()
 	}
 
-	feedTitle, _ := ui.feedList.GetItemText(0)
-	assert.Equal(t, "Test Feed Title From Parser", feedTitle)
+	feedTitle, _ := ui.feedList.GetItemText(1)
+	assert.Equal(t, "Test Feed Title From Parser (2)", feedTitle)
+}
+
+func TestUpdateFeedRowWhileFetching(t *testing.T) {
+	data := createTestData(false)
+	app := CreateStubbedApp(true)
+	ui := CreateUI(app, data)
+
+	data.safeFeedData.Clear()
+	ui.setupLists()
+
+	feedTitle, _ := ui.feedList.GetItemText(2)
+	assert.Equal(t, "Fetching "+testURLTwo, feedTitle)
+
+	data.safeFeedData.SetSiteData(testURLTwo, createFakeFeedDataModel("google", testURLTwo))
+	ui.updateFeedRow(testURLTwo)
+
+	for _, f := range ui.app.(*StubbedApp).UpdateDraws {
+		f()
+	}
+
+	feedTitle, _ = ui.feedList.GetItemText(2)
+	assert.Equal(t, "google (2)", feedTitle)
+	feedTitle, _ = ui.feedList.GetItemText(1)
+	assert.Equal(t, "Fetching "+testURLOne, feedTitle)
+	feedTitle, _ = ui.feedList.GetItemText(0)
+	assert.Equal(t, "All (2)", feedTitle)
+}
+
+func TestUpdateFeedRowKeepsSelectedEntryAndFocus(t *testing.T) {
+	data := createTestData(false)
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+
+	ui.switchAppFocus(ui.entriesList.Box, ui.feedList.Box, ui.entriesList)
+	ui.entriesList.SetCurrentItem(1)
+
+	refreshed := createFakeFeedDataModel("registry", testURLOne)
+	refreshed.entries = append([]Entry{{guid: "new", title: "new entry", url: testURLOne + "/new"}}, refreshed.entries...)
+	refreshed.entries[2].read = true
+	data.safeFeedData.SetSiteData(testURLOne, refreshed)
+
+	ui.reloadEntries(testURLOne)
+
+	assert.Equal(t, ui.entriesList, ui.app.GetFocus())
+	assert.Equal(t, 3, ui.entriesList.GetItemCount())
+	assert.Equal(t, 2, ui.entriesList.GetCurrentItem())
+	assert.Contains(t, ui.entryTextView.GetText(true), "registry fake content two")
+	assert.False(t, data.safeFeedData.GetEntries(testURLOne).entries[0].read)
 }
 
 func TestLoadAllFeedDataAndUpdateInterfaceWithError(t *testing.T) {
 	data := createTestData(true)
+	data.safeFeedData.Clear()
+	app := CreateStubbedApp(true)
+	ui := CreateUI(app, data)
+
+	ui.loadAllFeedDataAndUpdateInterface()
+
+	for _, f := range ui.app.(*StubbedApp).UpdateDraws {
+		f()
+	}
+
+	pageName, _ := ui.pages.GetFrontPage()
+	assert.Equal(t, feedPage, pageName)
+
+	ui.feedList.SetCurrentItem(1)
+	feedTitle, _ := ui.feedList.GetItemText(1)
+	assert.Equal(t, "[red]Failed "+testURLOne+" - error loading feed: stubbed parser error", feedTitle)
+
+	entryTitle, _ := ui.entriesList.GetItemText(0)
+	assert.Equal(t, "Failed to load feed", entryTitle)
+	assert.Equal(t, "error loading feed: stubbed parser error", ui.entryTextView.GetText(true))
+}
+
+func TestLoadAllFeedDataWithErrorKeepsLoadedEntries(t *testing.T) {
+	data := createTestData(true)
+	app := CreateStubbedApp(true)
+	ui := CreateUI(app, data)
+
+	ui.loadAllFeedDataAndUpdateInterface()
+
+	for _, f := range ui.app.(*StubbedApp).UpdateDraws {
+		f()
+	}
+
+	ui.feedList.SetCurrentItem(1)
+	feedTitle, _ := ui.feedList.GetItemText(1)
+	assert.Equal(t, "[red]Failed "+testURLOne+" - error loading feed: stubbed parser error", feedTitle)
+
+	assert.Equal(t, 2, ui.entriesList.GetItemCount())
+	entryTitle, _ := ui.entriesList.GetItemText(0)
+	assert.Equal(t, "[::b]registry fake title one", entryTitle)
+}
+
+func TestLoadAllFeedDataAndUpdateInterfaceWithoutFeeds(t *testing.T) {
+	data := createTestData(false)
+	data.configData = &ConfigData{}
 	app := CreateStubbedApp(true)
 	ui := CreateUI(app, data)
 
@@ -424,7 +584,8 @@ func TestLoadAllFeedDataAndUpdateInterfaceWithError(t *testing.T) {
 	}
 
 	pageName, _ := ui.pages.GetFrontPage()
-	assert.Equal(t, errorPage, pageName)
+	assert.Equal(t, feedPage, pageName)
+	assert.Equal(t, 1, ui.feedList.GetItemCount())
 }
 
 func setupWithSimScreen(data *Data) (tcell.SimulationScreen, *UI) {
@@ -451,11 +612,11 @@ func getScreenContents(simScreen tcell.SimulationScreen) string {
 }
 
 func createTestData(withError bool) *Data {
-	safeFeedData := &SafeFeedData{feedData: make(map[string]FeedDataModel)}
+	safeFeedData := &SafeFeedData{feedData: make(map[string]FeedDataModel), index: NewSearchIndex()}
 	safeFeedData.SetSiteData(testURLOne, createFakeFeedDataModel("registry", testURLOne))
 	safeFeedData.SetSiteData(testURLTwo, createFakeFeedDataModel("google", testURLTwo))
 
-	allFeeds := &ConfigData{[]Feed{{testURLOne}, {testURLTwo}}}
+	allFeeds := &ConfigData{Feeds: []Feed{{URL: testURLOne}, {URL: testURLTwo}}}
 
 	fakeFeed := CreateTestFeed()
 	parser := createStubbedParser(&fakeFeed, withError)
@@ -465,12 +626,14 @@ func createTestData(withError bool) *Data {
 
 func createFakeFeedDataModel(name, url string) FeedDataModel {
 	fakeEntryOne := Entry{
+		guid:    url + "/one",
 		title:   name + " fake title one",
 		url:     url + "/one",
 		content: name + " fake content one",
 	}
 
 	fakeEntryTwo := Entry{
+		guid:    url + "/two",
 		title:   name + " fake title two",
 		url:     url + "/two",
 		content: name + " fake content two",
@@ -482,3 +645,278 @@ func createFakeFeedDataModel(name, url string) FeedDataModel {
 	}
 	return fakeFeedDataModelOne
 }
+
+func TestNavigatingEntriesMarksThemRead(t *testing.T) {
+	data := createTestData(false)
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+
+	ui.feedList.SetCurrentItem(1)
+	keyEvent := tcell.NewEventKey(tcell.KeyEnter, rune(0), 0)
+	ui.feedList.InputHandler()(keyEvent, nil)
+
+	assert.True(t, data.safeFeedData.GetEntries(testURLOne).entries[0].read)
+	assert.False(t, data.safeFeedData.GetEntries(testURLOne).entries[1].read)
+
+	feedName, _ := ui.feedList.GetItemText(1)
+	assert.Equal(t, "registry (1)", feedName)
+	feedName, _ = ui.feedList.GetItemText(0)
+	assert.Equal(t, "All (3)", feedName)
+
+	ui.entriesList.SetCurrentItem(1)
+
+	assert.True(t, data.safeFeedData.GetEntries(testURLOne).entries[1].read)
+	feedName, _ = ui.feedList.GetItemText(1)
+	assert.Equal(t, "registry", feedName)
+	entryName, _ := ui.entriesList.GetItemText(1)
+	assert.Equal(t, "registry fake title two", entryName)
+}
+
+func TestBrowsingFeedListDoesNotMarkEntriesRead(t *testing.T) {
+	data := createTestData(false)
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+
+	ui.feedList.SetCurrentItem(1)
+
+	assert.Equal(t, 2, data.safeFeedData.GetEntries(testURLOne).unreadCount())
+	assert.Equal(t, 2, data.safeFeedData.GetEntries(testURLTwo).unreadCount())
+}
+
+func TestHandleKeyboardPressToggleReadAndMarkFeedRead(t *testing.T) {
+	data := createTestData(false)
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+	ui.setInputCaptureHandler()
+	ui.feedList.SetCurrentItem(1)
+
+	ui.app.GetInputCapture()(tcell.NewEventKey(tcell.KeyRune, 'u', 0))
+	assert.True(t, data.safeFeedData.GetEntries(testURLOne).entries[0].read)
+
+	ui.app.GetInputCapture()(tcell.NewEventKey(tcell.KeyRune, 'u', 0))
+	assert.False(t, data.safeFeedData.GetEntries(testURLOne).entries[0].read)
+
+	ui.app.GetInputCapture()(tcell.NewEventKey(tcell.KeyRune, 'a', 0))
+	assert.Equal(t, 0, data.safeFeedData.GetEntries(testURLOne).unreadCount())
+	assert.Equal(t, 2, data.safeFeedData.GetEntries(testURLTwo).unreadCount())
+
+	feedName, _ := ui.feedList.GetItemText(1)
+	assert.Equal(t, "registry", feedName)
+}
+
+func TestCreateMessagePage(t *testing.T) {
+	data := createTestData(false)
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+
+	messageModal := ui.createMessagePage("test message")
+
+	messageModal.Draw(simScreen)
+	assert.Contains(t, getScreenContents(simScreen), "test message")
+
+	button, ok := ui.app.GetFocus().(*tview.Button)
+	assert.True(t, ok)
+	button.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, rune(0), 0), nil)
+
+	frontPage, _ := ui.pages.GetFrontPage()
+	assert.Equal(t, feedPage, frontPage)
+	assert.Equal(t, ui.feedList, ui.app.GetFocus())
+}
+
+func TestEntryTextViewShowsMetadataHeader(t *testing.T) {
+	published := time.Date(2021, time.May, 3, 9, 30, 0, 0, time.UTC)
+	data := createTestData(false)
+	feedData := data.safeFeedData.GetEntries(testURLOne)
+	feedData.entries[0].author = "Simon [Travaglia]"
+	feedData.entries[0].published = published
+	feedData.entries[0].updated = published.Add(time.Hour)
+	feedData.entries[0].categories = []string{"bofh", "humour"}
+	feedData.entries[0].content = "content with [brackets]"
+	data.safeFeedData.SetSiteData(testURLOne, feedData)
+
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+
+	text := ui.entryTextView.GetText(true)
+	assert.Contains(t, text, "Author: Simon [Travaglia]\n")
+	assert.Contains(t, text, "Published: "+published.Local().Format(headerDateFormat)+"\n")
+	assert.Contains(t, text, "Updated: "+published.Add(time.Hour).Local().Format(headerDateFormat)+"\n")
+	assert.Contains(t, text, "Tags: bofh, humour\n")
+	assert.Contains(t, text, "Link: "+testURLOne+"/one\n")
+	assert.True(t, strings.HasSuffix(text, "\ncontent with [brackets]"))
+}
+
+func TestEntryHeaderLeavesOutMissingFields(t *testing.T) {
+	assert.Equal(t, "", entryHeader(Entry{}))
+	assert.Equal(t, "[::b]Link:[::-] example.com\n", entryHeader(Entry{url: "example.com"}))
+}
+
+func TestEntriesListShowsDatesWhenConfigured(t *testing.T) {
+	published := time.Date(2021, time.May, 3, 12, 0, 0, 0, time.UTC)
+	data := createTestData(false)
+	data.configData.ShowEntryDates = true
+	feedData := data.safeFeedData.GetEntries(testURLOne)
+	feedData.entries[0].published = published
+	feedData.entries[1].read = true
+	data.safeFeedData.SetSiteData(testURLOne, feedData)
+
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+	ui.feedList.SetCurrentItem(1)
+
+	entryText, _ := ui.entriesList.GetItemText(0)
+	assert.Equal(t, "[gray]"+published.Local().Format(listDateFormat)+"[-] [::b]registry fake title one", entryText)
+
+	entryText, _ = ui.entriesList.GetItemText(1)
+	assert.Equal(t, "[gray]          [-] registry fake title two", entryText)
+}
+
+func TestAllFeedsRowListsEntriesOfEveryFeedNewestFirst(t *testing.T) {
+	data := createTestData(false)
+	feedData := data.safeFeedData.GetEntries(testURLTwo)
+	feedData.entries[1].published = time.Date(2021, time.May, 3, 12, 0, 0, 0, time.UTC)
+	data.safeFeedData.SetSiteData(testURLTwo, feedData)
+
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+
+	assert.Equal(t, allFeedsURL, ui.getSelectedFeedURL())
+	assert.Equal(t, 4, ui.entriesList.GetItemCount())
+
+	entryText, _ := ui.entriesList.GetItemText(0)
+	assert.Equal(t, "[darkcyan]google[-:-:-] [::b]google fake title two", entryText)
+	entryText, _ = ui.entriesList.GetItemText(1)
+	assert.Equal(t, "[darkcyan]registry[-:-:-] [::b]registry fake title one", entryText)
+
+	ui.switchAppFocus(ui.entriesList.Box, ui.feedList.Box, ui.entriesList)
+	ui.entriesList.SetCurrentItem(0)
+	ui.setEntryRead(0, true)
+	assert.True(t, data.safeFeedData.GetEntries(testURLTwo).entries[1].read)
+
+	feedName, _ := ui.feedList.GetItemText(0)
+	assert.Equal(t, "All (3)", feedName)
+	feedName, _ = ui.feedList.GetItemText(2)
+	assert.Equal(t, "google (1)", feedName)
+
+	ui.markSelectedFeedRead()
+	assert.Equal(t, 0, data.safeFeedData.GetEntries(testURLOne).unreadCount())
+	assert.Equal(t, 0, data.safeFeedData.GetEntries(testURLTwo).unreadCount())
+}
+
+func TestSetEntryReadUsesListedEntriesWhileFeedsRefresh(t *testing.T) {
+	data := createTestData(false)
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+	ui.feedList.SetCurrentItem(1)
+
+	// a refresh lands before the rows are reloaded, putting a new entry above the listed ones
+	feedData := data.safeFeedData.GetEntries(testURLOne)
+	feedData.entries = append([]Entry{{guid: testURLOne + "/new", title: "new entry"}}, feedData.entries...)
+	data.safeFeedData.SetSiteData(testURLOne, feedData)
+
+	ui.setEntryRead(0, true)
+
+	entries := data.safeFeedData.GetEntries(testURLOne).entries
+	assert.False(t, entries[0].read)
+	assert.True(t, entries[1].read)
+	assert.True(t, ui.listedEntries()[0].read)
+	entryText, _ := ui.entriesList.GetItemText(0)
+	assert.Equal(t, "registry fake title one", entryText)
+}
+
+func TestHandleKeyboardPressCyclesSortMode(t *testing.T) {
+	data := createTestData(false)
+	feedData := data.safeFeedData.GetEntries(testURLOne)
+	feedData.entries[0].published = time.Date(2021, time.May, 3, 12, 0, 0, 0, time.UTC)
+	feedData.entries[1].published = time.Date(2021, time.May, 4, 12, 0, 0, 0, time.UTC)
+	data.safeFeedData.SetSiteData(testURLOne, feedData)
+
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+	ui.setInputCaptureHandler()
+	ui.feedList.SetCurrentItem(1)
+
+	entryText, _ := ui.entriesList.GetItemText(0)
+	assert.Equal(t, "[::b]registry fake title one", entryText)
+
+	ui.app.GetInputCapture()(tcell.NewEventKey(tcell.KeyRune, 's', 0))
+	assert.Equal(t, newestFirst, ui.sortMode)
+	assert.Equal(t, "Entries (newest first)", ui.entriesList.GetTitle())
+	entryText, _ = ui.entriesList.GetItemText(0)
+	assert.Equal(t, "[::b]registry fake title two", entryText)
+
+	ui.app.GetInputCapture()(tcell.NewEventKey(tcell.KeyRune, 's', 0))
+	assert.Equal(t, oldestFirst, ui.sortMode)
+	entryText, _ = ui.entriesList.GetItemText(0)
+	assert.Equal(t, "[::b]registry fake title one", entryText)
+}
+
+func TestSearchShowsResultsInEntriesList(t *testing.T) {
+	data := createTestData(false)
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+	ui.setInputCaptureHandler()
+
+	assert.Nil(t, ui.app.GetInputCapture()(tcell.NewEventKey(tcell.KeyRune, '/', 0)))
+	searchField, ok := ui.app.GetFocus().(*tview.InputField)
+	assert.True(t, ok)
+
+	// typing into the prompt doesn't trigger shortcuts
+	event := tcell.NewEventKey(tcell.KeyRune, 'q', 0)
+	assert.Equal(t, event, ui.app.GetInputCapture()(event))
+	frontPage, _ := ui.pages.GetFrontPage()
+	assert.Equal(t, searchPage, frontPage)
+
+	searchField.SetText("google two")
+	searchField.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, rune(0), 0), nil)
+
+	frontPage, _ = ui.pages.GetFrontPage()
+	assert.Equal(t, feedPage, frontPage)
+	assert.Equal(t, ui.entriesList, ui.app.GetFocus())
+	assert.Equal(t, "Search: google two (1)", ui.entriesList.GetTitle())
+	entryText, _ := ui.entriesList.GetItemText(0)
+	assert.Equal(t, "[darkcyan]google[-:-:-] google fake title two", entryText)
+	assert.Contains(t, ui.entryTextView.GetText(true), "google fake content two")
+	assert.True(t, data.safeFeedData.GetEntries(testURLTwo).entries[1].read)
+
+	ui.entriesList.InputHandler()(tcell.NewEventKey(tcell.KeyESC, rune(0), 0), nil)
+
+	assert.Equal(t, ui.feedList, ui.app.GetFocus())
+	assert.Equal(t, "Entries", ui.entriesList.GetTitle())
+	assert.Equal(t, 4, ui.entriesList.GetItemCount())
+}
+
+func TestSearchResultsAreKeptUntilTheIndexChanges(t *testing.T) {
+	data := createTestData(false)
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+
+	ui.showSearchResults("google two")
+	assert.Equal(t, 1, ui.entriesList.GetItemCount())
+
+	// reading a result doesn't change the index, the results are only brought up to date
+	ui.setEntryRead(0, true)
+	version := ui.searchVersion
+	ui.reloadEntries(searchResultsURL)
+	assert.Equal(t, version, ui.searchVersion)
+	assert.True(t, ui.listedEntries()[0].read)
+
+	feedData := data.safeFeedData.GetEntries(testURLTwo)
+	feedData.entries = append(feedData.entries, Entry{guid: testURLTwo + "/three", title: "google two more"})
+	data.safeFeedData.SetSiteData(testURLTwo, feedData)
+	ui.reloadEntries(searchResultsURL)
+
+	assert.NotEqual(t, version, ui.searchVersion)
+	assert.Equal(t, 2, ui.entriesList.GetItemCount())
+}
+
+func TestSearchWithoutResults(t *testing.T) {
+	data := createTestData(false)
+	simScreen, ui := setupWithSimScreen(data)
+	defer simScreen.Fini()
+
+	ui.showSearchResults("nothing")
+
+	assert.Equal(t, 0, ui.entriesList.GetItemCount())
+	assert.Equal(t, "No entries match nothing", ui.entryTextView.GetText(true))
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"unicode/utf8"
+)
+
+// defaultVimLeader key pressed before a shortcut in vim mode when the config doesn't set one
+const defaultVimLeader = " "
+
+// vimHelp keys of vim mode and what they do, listed in the help page
+var vimHelp = [][2]string{
+	{"j, k", "move down and up, counts such as 5j move that many rows"},
+	{"g g, G", "jump to the top and bottom, 5G jumps to row 5"},
+	{"Ctrl-D, Ctrl-U", "page the description down and up"},
+	{"h, l", "move between the feed, entries and description panes"},
+	{"n, N", "jump to the next and previous unread feed or entry"},
+}
+
+// vimState keys typed so far of a vim command that takes more than one key
+type vimState struct {
+	count         int
+	pendingG      bool
+	pendingLeader bool
+}
+
+// handle a key pressed in vim mode, shortcuts that type a character have to follow the leader key,
+// returns the event passed on to the focused primitive
+func (ui *UI) handleVimKey(event *tcell.EventKey) *tcell.EventKey {
+	state := ui.vim
+	ui.vim = vimState{}
+
+	if state.pendingLeader {
+		return ui.runShortcut(event)
+	}
+	if keyName(event) == ui.vimLeader {
+		ui.vim.pendingLeader = true
+		return nil
+	}
+
+	r := event.Rune()
+	if event.Key() == tcell.KeyRune && r >= '0' && r <= '9' && (r != '0' || state.count > 0) {
+		ui.vim = vimState{count: state.count*10 + int(r-'0'), pendingG: state.pendingG}
+		return nil
+	}
+
+	count := state.count
+	if count == 0 {
+		count = 1
+	}
+	// rows jumped to by g and G are counted from 1, with no count they jump to the top and bottom
+	row := state.count - 1
+
+	switch event.Key() {
+	case tcell.KeyCtrlD:
+		ui.scrollEntryText(count * ui.halfPage())
+		return nil
+	case tcell.KeyCtrlU:
+		ui.scrollEntryText(-count * ui.halfPage())
+		return nil
+	case tcell.KeyRune:
+		switch r {
+		case 'j':
+			ui.vimMove(count)
+		case 'k':
+			ui.vimMove(-count)
+		case 'g':
+			if !state.pendingG {
+				ui.vim = vimState{count: state.count, pendingG: true}
+				return nil
+			}
+			if row < 0 {
+				row = 0
+			}
+			ui.vimJump(row)
+		case 'G':
+			ui.vimJump(row)
+		case 'h':
+			return ui.vimLeft()
+		case 'l':
+			return ui.vimRight()
+		case 'n':
+			ui.jumpToUnread(count)
+		case 'N':
+			ui.jumpToUnread(-count)
+		default:
+			return event
+		}
+		return nil
+	}
+	return ui.runShortcut(event)
+}
+
+// move the selection of the focused list, or scroll the description when it has focus
+func (ui *UI) vimMove(offset int) {
+	switch ui.app.GetFocus() {
+	case ui.feedList:
+		moveSelection(ui.feedList, offset)
+	case ui.entriesList:
+		moveSelection(ui.entriesList, offset)
+	case ui.entryTextView:
+		ui.scrollEntryText(offset)
+	}
+}
+
+// select row of the focused list or scroll the description to it, a negative row is the last one
+func (ui *UI) vimJump(row int) {
+	switch ui.app.GetFocus() {
+	case ui.feedList:
+		ui.feedList.SetCurrentItem(row)
+	case ui.entriesList:
+		ui.entriesList.SetCurrentItem(row)
+	case ui.entryTextView:
+		if row < 0 {
+			ui.entryTextView.ScrollToEnd()
+		} else {
+			ui.entryTextView.ScrollTo(row, 0)
+		}
+	}
+}
+
+// move focus one pane to the left, leaving the entries list works like Esc so search results are left too
+func (ui *UI) vimLeft() *tcell.EventKey {
+	switch ui.app.GetFocus() {
+	case ui.entriesList:
+		return tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone)
+	case ui.entryTextView:
+		ui.switchAppFocus(ui.entriesList.Box, ui.entryTextView.Box, ui.entriesList)
+	}
+	return nil
+}
+
+// move focus one pane to the right, entering the entries list works like Enter so the entry shown is read
+func (ui *UI) vimRight() *tcell.EventKey {
+	switch ui.app.GetFocus() {
+	case ui.feedList:
+		return tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	case ui.entriesList:
+		ui.switchAppFocus(ui.entryTextView.Box, ui.entriesList.Box, ui.entryTextView)
+	}
+	return nil
+}
+
+// scroll the description by lines, negative lines scroll up
+func (ui *UI) scrollEntryText(lines int) {
+	row, column := ui.entryTextView.GetScrollOffset()
+	row += lines
+	if row < 0 {
+		row = 0
+	}
+	ui.entryTextView.ScrollTo(row, column)
+}
+
+// half the height of the description, how far Ctrl-D and Ctrl-U page it
+func (ui *UI) halfPage() int {
+	_, _, _, height := ui.entryTextView.GetInnerRect()
+	if height < 2 {
+		return 1
+	}
+	return height / 2
+}
+
+// select the count'th unread feed or entry after the selected one, or before it when count is negative,
+// the description pane jumps between the unread entries of the entries list
+func (ui *UI) jumpToUnread(count int) {
+	list := ui.entriesList
+	entries := ui.listedEntries()
+	unread := func(i int) bool {
+		return i < len(entries) && !entries[i].read
+	}
+	if ui.app.GetFocus() == ui.feedList {
+		list = ui.feedList
+		unread = func(i int) bool {
+			_, url := ui.feedList.GetItemText(i)
+			return url != allFeedsURL && ui.data.safeFeedData.GetEntries(url).unreadCount() > 0
+		}
+	}
+
+	step := 1
+	if count < 0 {
+		step, count = -1, -count
+	}
+	target := -1
+	for i := list.GetCurrentItem() + step; i >= 0 && i < list.GetItemCount() && count > 0; i += step {
+		if unread(i) {
+			target = i
+			count--
+		}
+	}
+	if target < 0 {
+		return
+	}
+
+	list.SetCurrentItem(target)
+	// the entries list only marks entries read as it is moved through when it has focus
+	if ui.app.GetFocus() == ui.entryTextView {
+		ui.setEntryRead(target, true)
+	}
+}
+
+// name of key as it is shown in the menu and help page
+func displayKeyName(key string) string {
+	if key == " " {
+		return "Space"
+	}
+	return key
+}
+
+// name of a key bound to a shortcut, shortcuts that type a character follow the leader in vim mode
+func (ui *UI) shortcutName(key string) string {
+	if ui.vimMode && utf8.RuneCountInString(key) == 1 {
+		return fmt.Sprintf("%s %s", tview.Escape(displayKeyName(ui.vimLeader)), tview.Escape(displayKeyName(key)))
+	}
+	return tview.Escape(displayKeyName(key))
+}
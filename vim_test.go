@@ -0,0 +1,116 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func setupVimMode() (tcell.SimulationScreen, *UI, func(keys ...*tcell.EventKey) *tcell.EventKey) {
+	data := createTestData(false)
+	data.configData.VimMode = true
+	simScreen, ui := setupWithSimScreen(data)
+	ui.setInputCaptureHandler()
+	capture := ui.app.GetInputCapture()
+	press := func(keys ...*tcell.EventKey) *tcell.EventKey {
+		var event *tcell.EventKey
+		for _, key := range keys {
+			event = capture(key)
+		}
+		return event
+	}
+	return simScreen, ui, press
+}
+
+func runes(text string) []*tcell.EventKey {
+	var keys []*tcell.EventKey
+	for _, r := range text {
+		keys = append(keys, tcell.NewEventKey(tcell.KeyRune, r, 0))
+	}
+	return keys
+}
+
+func TestVimModeMovesWithCounts(t *testing.T) {
+	simScreen, ui, press := setupVimMode()
+	defer simScreen.Fini()
+
+	assert.Nil(t, press(runes("j")...))
+	assert.Equal(t, 1, ui.feedList.GetCurrentItem())
+
+	press(runes("5j")...)
+	assert.Equal(t, 2, ui.feedList.GetCurrentItem())
+
+	press(runes("gg")...)
+	assert.Equal(t, 0, ui.feedList.GetCurrentItem())
+
+	press(runes("G")...)
+	assert.Equal(t, 2, ui.feedList.GetCurrentItem())
+
+	press(runes("2G")...)
+	assert.Equal(t, 1, ui.feedList.GetCurrentItem())
+
+	press(runes("k")...)
+	assert.Equal(t, 0, ui.feedList.GetCurrentItem())
+}
+
+func TestVimModeMovesBetweenPanes(t *testing.T) {
+	simScreen, ui, press := setupVimMode()
+	defer simScreen.Fini()
+
+	assert.Equal(t, tcell.KeyEnter, press(runes("l")...).Key())
+
+	ui.switchAppFocus(ui.entriesList.Box, ui.feedList.Box, ui.entriesList)
+	press(runes("l")...)
+	assert.Equal(t, ui.entryTextView, ui.app.GetFocus())
+
+	ui.entryTextView.SetText(strings.Repeat("line\n", 300))
+	press(tcell.NewEventKey(tcell.KeyCtrlD, 0, tcell.ModCtrl))
+	row, _ := ui.entryTextView.GetScrollOffset()
+	assert.Equal(t, ui.halfPage(), row)
+	press(runes("3j")...)
+	row, _ = ui.entryTextView.GetScrollOffset()
+	assert.Equal(t, ui.halfPage()+3, row)
+	press(tcell.NewEventKey(tcell.KeyCtrlU, 0, tcell.ModCtrl), tcell.NewEventKey(tcell.KeyCtrlU, 0, tcell.ModCtrl))
+	row, _ = ui.entryTextView.GetScrollOffset()
+	assert.Equal(t, 0, row)
+
+	press(runes("h")...)
+	assert.Equal(t, ui.entriesList, ui.app.GetFocus())
+	assert.Equal(t, tcell.KeyEscape, press(runes("h")...).Key())
+}
+
+func TestVimModeJumpsToUnread(t *testing.T) {
+	simScreen, ui, press := setupVimMode()
+	defer simScreen.Fini()
+	data := ui.data
+	data.safeFeedData.SetEntriesRead(testURLOne, []string{testURLOne + "/one", testURLOne + "/two"}, true)
+	ui.updateReadState()
+
+	press(runes("n")...)
+	assert.Equal(t, 2, ui.feedList.GetCurrentItem())
+	press(runes("N")...)
+	assert.Equal(t, 2, ui.feedList.GetCurrentItem())
+
+	ui.switchAppFocus(ui.entryTextView.Box, ui.feedList.Box, ui.entryTextView)
+	press(runes("n")...)
+	assert.Equal(t, 1, ui.entriesList.GetCurrentItem())
+	assert.True(t, data.safeFeedData.GetEntries(testURLTwo).entries[1].read)
+}
+
+func TestVimModePutsShortcutsBehindLeader(t *testing.T) {
+	simScreen, ui, press := setupVimMode()
+	defer simScreen.Fini()
+
+	press(runes("h")...)
+	frontPage, _ := ui.pages.GetFrontPage()
+	assert.Equal(t, feedPage, frontPage)
+
+	press(runes(" h")...)
+	frontPage, helpModal := ui.pages.GetFrontPage()
+	assert.Equal(t, helpPage, frontPage)
+	helpModal.Draw(simScreen)
+	assert.Contains(t, getScreenContents(simScreen), "Space q to quit")
+	assert.Contains(t, getScreenContents(simScreen), "j, k to move down and up")
+	assert.Contains(t, ui.menuTextView.GetText(true), "(Space r) Refresh")
+}